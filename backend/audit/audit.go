@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/retention"
+	"coaching-backend/services"
+	"log/slog"
+	"strconv"
+)
+
+func Record(event events.Event) {
+	entry := models.AuditEntry{
+		ID:         event.ID,
+		Action:     event.Type,
+		EntityType: events.Entity(event.Type),
+		CreatedAt:  event.OccurredAt,
+	}
+
+	switch data := event.Data.(type) {
+	case models.Feedback:
+		entry.EntityID = data.ID
+		entry.Details = map[string]string{"target_type": data.TargetType, "target_id": data.TargetID}
+		if data.AuthorID != nil {
+			entry.Details["author_id"] = *data.AuthorID
+		}
+		if data.GoalID != nil {
+			entry.Details["goal_id"] = *data.GoalID
+		}
+	case models.TeamMember:
+		entry.EntityID = data.ID
+	case models.Team:
+		entry.EntityID = data.ID
+	case models.Goal:
+		entry.EntityID = data.ID
+		entry.Details = map[string]string{"owner_type": data.OwnerType, "owner_id": data.OwnerID, "status": data.Status, "progress": strconv.Itoa(data.Progress)}
+	case events.MembershipChange:
+		entry.EntityID = data.Member.ID
+		entry.Details = map[string]string{}
+		if data.TeamID != nil {
+			entry.Details["team_id"] = *data.TeamID
+		}
+		if data.PreviousTeamID != nil {
+			entry.Details["previous_team_id"] = *data.PreviousTeamID
+		}
+	case services.ErasureReceipt:
+		entry.EntityID = data.MemberID
+		entry.Details = map[string]string{"receipt_id": data.ID, "signature": data.Signature}
+	case retention.Purge:
+		entry.EntityID = data.FeedbackID
+		entry.Details = map[string]string{"policy": data.Policy, "target_type": data.TargetType, "target_id": data.TargetID}
+		if data.Category != "" {
+			entry.Details["category"] = data.Category
+		}
+	case events.Ref:
+		entry.EntityID = data.ID
+	}
+
+	if err := database.DB.Create(&entry).Error; err != nil {
+		slog.Error("Failed to record audit entry", "event_id", event.ID, "event_type", event.Type, "error", err)
+	}
+}
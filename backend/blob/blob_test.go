@@ -0,0 +1,45 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	store := NewLocal(t.TempDir())
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "avatars/m1/abc-64.jpg", []byte("jpeg"), "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	object, err := store.Get(ctx, "avatars/m1/abc-64.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(object.Body)
+	object.Body.Close()
+	if string(data) != "jpeg" || object.ContentType != "image/jpeg" || object.Size != 4 {
+		t.Errorf("unexpected object %q %s %d", data, object.ContentType, object.Size)
+	}
+
+	if err := store.Delete(ctx, "avatars/m1/abc-64.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, "avatars/m1/abc-64.jpg"); err != nil {
+		t.Errorf("deleting a missing blob should succeed, got %v", err)
+	}
+	if _, err := store.Get(ctx, "avatars/m1/abc-64.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := store.Get(ctx, "avatars"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("directories are not blobs, got %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "avatars/../../secret", "a//b", "a\\b"} {
+		if err := store.Put(ctx, key, nil, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) should be rejected, got %v", key, err)
+		}
+	}
+}
@@ -4,10 +4,10 @@ echo "Building coaching backend..."
 
 go mod tidy
 
-go build -o bin/coaching-backend .
+go build -o bin/coaching-backend . && go build -o bin/coachctl ./cmd/coachctl && go build -o bin/reencrypt ./cmd/reencrypt
 
 if [ $? -eq 0 ]; then
-    echo "Build successful! Binary created at bin/coaching-backend"
+    echo "Build successful! Binaries created at bin/coaching-backend, bin/coachctl and bin/reencrypt"
 else
     echo "Build failed!"
     exit 1
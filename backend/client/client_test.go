@@ -0,0 +1,289 @@
+package client_test
+
+import (
+	"bytes"
+	"coaching-backend/blob"
+	"coaching-backend/client"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/routes"
+	"coaching-backend/services"
+	"context"
+	"errors"
+	"go/parser"
+	"go/token"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func init() {
+	events.Subscribe(events.Stream.Publish)
+}
+
+func setupServer(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, models.AutoMigrate(db))
+	database.DB = db
+
+	r := gin.New()
+	routes.Register(r)
+	return r
+}
+
+func TestClientDoesNotImportServerPackages(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		require.NoError(t, err)
+		for _, spec := range parsed.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			assert.False(t, strings.HasPrefix(path, "coaching-backend/"), "%s imports %s", file, path)
+			assert.False(t, strings.Contains(path, "."), "%s imports %s", file, path)
+		}
+	}
+}
+
+func TestClientAgainstRouter(t *testing.T) {
+	server := httptest.NewServer(setupServer(t))
+	defer server.Close()
+
+	ctx := context.Background()
+	api := client.New(server.URL)
+
+	health, err := api.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", health["status"])
+
+	team, err := api.CreateTeam(ctx, client.Team{Name: "Platform"})
+	require.NoError(t, err)
+	member, err := api.CreateMember(ctx, client.TeamMember{Name: "Ada", Email: "ada@example.com"})
+	require.NoError(t, err)
+
+	require.NoError(t, api.AssignMember(ctx, client.AssignRequest{MemberID: member.ID, TeamID: team.ID}))
+	fetched, err := api.GetTeam(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, fetched.Members, 1)
+
+	stale := ""
+	err = api.AssignMember(ctx, client.AssignRequest{MemberID: member.ID, TeamID: team.ID, ExpectedTeamID: &stale})
+	assert.True(t, client.IsConflict(err))
+
+	_, err = api.CreateFeedback(ctx, client.Feedback{Content: "Great demo", TargetType: "member", TargetID: member.ID})
+	require.NoError(t, err)
+	_, err = api.CreateFeedback(ctx, client.Feedback{Content: "Solid sprint", TargetType: "team", TargetID: team.ID})
+	require.NoError(t, err)
+
+	feedbacks, err := api.ListFeedbacks(ctx, client.FeedbackFilter{TargetType: "member", TargetID: member.ID})
+	require.NoError(t, err)
+	require.Len(t, feedbacks, 1)
+	assert.Equal(t, "Ada", feedbacks[0].TargetName)
+
+	goal, err := api.CreateGoal(ctx, client.Goal{OwnerType: "member", OwnerID: member.ID, Title: "Run a demo"})
+	require.NoError(t, err)
+	_, err = api.SetFeedbackGoal(ctx, feedbacks[0].ID, &goal.ID)
+	require.NoError(t, err)
+	linked, err := api.ListGoalFeedbacks(ctx, goal.ID)
+	require.NoError(t, err)
+	require.Len(t, linked, 1)
+	assert.Equal(t, feedbacks[0].ID, linked[0].ID)
+
+	var data struct {
+		Team struct {
+			Name    string `json:"name"`
+			Members []struct {
+				Name string `json:"name"`
+			} `json:"members"`
+		} `json:"team"`
+	}
+	require.NoError(t, api.GraphQL(ctx, `query($id: ID!) { team(id: $id) { name members { name } } }`, map[string]interface{}{"id": team.ID}, &data))
+	assert.Equal(t, "Ada", data.Team.Members[0].Name)
+
+	require.NoError(t, api.RemoveMember(ctx, member.ID, &team.ID))
+	require.NoError(t, api.DeleteTeam(ctx, team.ID))
+
+	_, err = api.GetTeam(ctx, team.ID)
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Team not found", apiErr.Title)
+	assert.Equal(t, "The requested team does not exist", apiErr.Message)
+
+	_, err = api.CreateTeam(ctx, client.Team{Name: "X"})
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Validation failed", apiErr.Title)
+	assert.Equal(t, "team name must be at least 2 characters", apiErr.Message)
+}
+
+func TestClientRetriesIdempotentCalls(t *testing.T) {
+	router := setupServer(t)
+	var failures, calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	api := client.New(server.URL, client.WithRetries(3, time.Millisecond))
+
+	atomic.StoreInt32(&failures, 2)
+	teams, err := api.ListTeams(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, teams)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&failures, 1)
+	_, err = api.CreateTeam(ctx, client.Team{Name: "Platform"})
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&failures, 10)
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = client.New(server.URL, client.WithRetries(10, 50*time.Millisecond)).ListTeams(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClientHonorsRetryAfter(t *testing.T) {
+	router := setupServer(t)
+	var calls int32
+	var retried time.Duration
+	var first time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		retried = time.Since(first)
+		router.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	_, err := client.New(server.URL, client.WithRetries(1, time.Millisecond)).ListTeams(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.GreaterOrEqual(t, retried, time.Second)
+
+	atomic.StoreInt32(&calls, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.New(server.URL, client.WithRetries(1, time.Millisecond)).ListTeams(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClientLegalHoldRetentionAndUploads(t *testing.T) {
+	server := httptest.NewServer(setupServer(t))
+	defer server.Close()
+	services.Blobs = blob.NewLocal(t.TempDir())
+
+	ctx := context.Background()
+	api := client.New(server.URL)
+
+	member, err := api.CreateMember(ctx, client.TeamMember{Name: "Ada", Email: "ada@example.com"})
+	require.NoError(t, err)
+	team, err := api.CreateTeam(ctx, client.Team{Name: "Platform"})
+	require.NoError(t, err)
+	feedback, err := api.CreateFeedback(ctx, client.Feedback{Content: "Great demo", TargetType: "member", TargetID: member.ID})
+	require.NoError(t, err)
+
+	held, err := api.SetMemberLegalHold(ctx, member.ID, true)
+	require.NoError(t, err)
+	assert.True(t, held.LegalHold)
+	heldFeedback, err := api.SetFeedbackLegalHold(ctx, feedback.ID, true)
+	require.NoError(t, err)
+	assert.True(t, heldFeedback.LegalHold)
+	_, err = api.SetFeedbackLegalHold(ctx, "missing", false)
+	assert.True(t, client.IsNotFound(err))
+
+	report, err := api.GetRetentionReport(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	report, err = api.RunRetention(ctx, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	var photo bytes.Buffer
+	require.NoError(t, png.Encode(&photo, img))
+
+	updated, err := api.UploadMemberAvatar(ctx, member.ID, "ada.png", photo.Bytes())
+	require.NoError(t, err)
+	assert.Contains(t, updated.Picture, "/media/avatars/"+member.ID+"/")
+	updated, err = api.DeleteMemberAvatar(ctx, member.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, updated.Picture, "/media/")
+
+	logo, err := api.UploadTeamLogo(ctx, team.ID, "logo.png", photo.Bytes())
+	require.NoError(t, err)
+	assert.Contains(t, logo.Logo, "/media/")
+	logo, err = api.DeleteTeamLogo(ctx, team.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, logo.Logo, "/media/")
+
+	_, err = api.UploadMemberAvatar(ctx, member.ID, "notes.txt", []byte("not an image"))
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnsupportedMediaType, apiErr.StatusCode)
+}
+
+func TestClientStreamEvents(t *testing.T) {
+	server := httptest.NewServer(setupServer(t))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	api := client.New(server.URL)
+
+	_, err := api.CreateMember(ctx, client.TeamMember{Name: "Ada", Email: "ada@example.com"})
+	require.NoError(t, err)
+	lastSeq := events.Stream.LastSeq()
+	team, err := api.CreateTeam(ctx, client.Team{Name: "Platform"})
+	require.NoError(t, err)
+
+	var received []client.Event
+	err = api.StreamEvents(ctx, client.EventFilter{
+		Entities:    []string{"team"},
+		LastEventID: strconv.FormatUint(lastSeq, 10),
+	}, func(event client.Event) error {
+		received = append(received, event)
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, received, 1)
+	assert.Equal(t, "team.created", received[0].Type)
+	assert.Equal(t, strconv.FormatUint(lastSeq+1, 10), received[0].Seq)
+	assert.Contains(t, string(received[0].Data), team.ID)
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type EventFilter struct {
+	Entities    []string
+	Types       []string
+	LastEventID string
+}
+
+type Event struct {
+	Seq        string          `json:"-"`
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func (c *Client) StreamEvents(ctx context.Context, filter EventFilter, handle func(Event) error) error {
+	query := url.Values{}
+	if len(filter.Entities) > 0 {
+		query.Set("entities", strings.Join(filter.Entities, ","))
+	}
+	if len(filter.Types) > 0 {
+		query.Set("types", strings.Join(filter.Types, ","))
+	}
+
+	req, err := c.request(ctx, http.MethodGet, "/api/v1/events", query, nil, "")
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if filter.LastEventID != "" {
+		req.Header.Set("Last-Event-ID", filter.LastEventID)
+	}
+
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return decodeResponse(resp, nil)
+	}
+	defer resp.Body.Close()
+
+	var seq, eventType string
+	var data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data.Len() > 0 {
+				event := Event{Seq: seq, Type: eventType}
+				if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+					return fmt.Errorf("decode event %s: %w", seq, err)
+				}
+				if event.Type == "" {
+					event.Type = eventType
+					event.Data = json.RawMessage(data.String())
+				}
+				if err := handle(event); err != nil {
+					return err
+				}
+			}
+			seq, eventType = "", ""
+			data.Reset()
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			seq = value
+		case "event":
+			eventType = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteString("\n")
+			}
+			data.WriteString(value)
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type FeedbackFilter struct {
+	TargetType string
+	TargetID   string
+	GoalID     string
+	Query      string
+}
+
+func (c *Client) CreateFeedback(ctx context.Context, feedback Feedback) (*Feedback, error) {
+	var created Feedback
+	if err := c.do(ctx, http.MethodPost, "/api/v1/feedbacks", nil, feedback, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) ListFeedbacks(ctx context.Context, filter FeedbackFilter) ([]Feedback, error) {
+	query := url.Values{}
+	if filter.TargetType != "" {
+		query.Set("target_type", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query.Set("target_id", filter.TargetID)
+	}
+	if filter.GoalID != "" {
+		query.Set("goal_id", filter.GoalID)
+	}
+	if filter.Query != "" {
+		query.Set("q", filter.Query)
+	}
+
+	var feedbacks []Feedback
+	err := c.do(ctx, http.MethodGet, "/api/v1/feedbacks", query, nil, &feedbacks)
+	return feedbacks, err
+}
+
+func (c *Client) GetFeedback(ctx context.Context, id string) (*Feedback, error) {
+	var feedback Feedback
+	if err := c.do(ctx, http.MethodGet, "/api/v1/feedbacks/"+url.PathEscape(id), nil, nil, &feedback); err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+func (c *Client) UpdateFeedback(ctx context.Context, id string, feedback Feedback) (*Feedback, error) {
+	var updated Feedback
+	if err := c.do(ctx, http.MethodPut, "/api/v1/feedbacks/"+url.PathEscape(id), nil, feedback, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteFeedback(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/feedbacks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c *Client) SetFeedbackLegalHold(ctx context.Context, id string, legalHold bool) (*Feedback, error) {
+	var feedback Feedback
+	if err := c.do(ctx, http.MethodPut, "/api/v1/feedbacks/"+url.PathEscape(id)+"/legal-hold", nil, LegalHoldRequest{LegalHold: legalHold}, &feedback); err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
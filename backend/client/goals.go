@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type GoalFilter struct {
+	OwnerType string
+	OwnerID   string
+	Status    string
+}
+
+func (c *Client) CreateGoal(ctx context.Context, goal Goal) (*Goal, error) {
+	var created Goal
+	if err := c.do(ctx, http.MethodPost, "/api/v1/goals", nil, goal, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) ListGoals(ctx context.Context, filter GoalFilter) ([]Goal, error) {
+	query := url.Values{}
+	if filter.OwnerType != "" {
+		query.Set("owner_type", filter.OwnerType)
+	}
+	if filter.OwnerID != "" {
+		query.Set("owner_id", filter.OwnerID)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+
+	var goals []Goal
+	err := c.do(ctx, http.MethodGet, "/api/v1/goals", query, nil, &goals)
+	return goals, err
+}
+
+func (c *Client) GetGoal(ctx context.Context, id string) (*Goal, error) {
+	var goal Goal
+	if err := c.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(id), nil, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (c *Client) UpdateGoal(ctx context.Context, id string, goal Goal) (*Goal, error) {
+	var updated Goal
+	if err := c.do(ctx, http.MethodPut, "/api/v1/goals/"+url.PathEscape(id), nil, goal, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteGoal(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/goals/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c *Client) ListGoalFeedbacks(ctx context.Context, id string) ([]Feedback, error) {
+	var feedbacks []Feedback
+	err := c.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(id)+"/feedbacks", nil, nil, &feedbacks)
+	return feedbacks, err
+}
+
+func (c *Client) SetFeedbackGoal(ctx context.Context, feedbackID string, goalID *string) (*Feedback, error) {
+	var feedback Feedback
+	body := map[string]*string{"goal_id": goalID}
+	if err := c.do(ctx, http.MethodPut, "/api/v1/feedbacks/"+url.PathEscape(feedbackID)+"/goal", nil, body, &feedback); err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
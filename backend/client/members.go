@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type LegalHoldRequest struct {
+	LegalHold bool `json:"legal_hold"`
+}
+
+type MemberFilter struct {
+	Skill          string
+	MinProficiency int
+	Level          string
+}
+
+func (c *Client) CreateMember(ctx context.Context, member TeamMember) (*TeamMember, error) {
+	var created TeamMember
+	if err := c.do(ctx, http.MethodPost, "/api/v1/members", nil, member, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) ListMembers(ctx context.Context, filter MemberFilter) ([]TeamMember, error) {
+	query := url.Values{}
+	if filter.Skill != "" {
+		query.Set("skill", filter.Skill)
+	}
+	if filter.MinProficiency != 0 {
+		query.Set("min_proficiency", strconv.Itoa(filter.MinProficiency))
+	}
+	if filter.Level != "" {
+		query.Set("level", filter.Level)
+	}
+
+	var members []TeamMember
+	err := c.do(ctx, http.MethodGet, "/api/v1/members", query, nil, &members)
+	return members, err
+}
+
+func (c *Client) GetMember(ctx context.Context, id string) (*TeamMember, error) {
+	var member TeamMember
+	if err := c.do(ctx, http.MethodGet, "/api/v1/members/"+url.PathEscape(id), nil, nil, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (c *Client) UpdateMember(ctx context.Context, id string, member TeamMember) (*TeamMember, error) {
+	var updated TeamMember
+	if err := c.do(ctx, http.MethodPut, "/api/v1/members/"+url.PathEscape(id), nil, member, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteMember(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/members/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c *Client) GetNotificationPreferences(ctx context.Context, memberID string) (*NotificationPreference, error) {
+	var preferences NotificationPreference
+	if err := c.do(ctx, http.MethodGet, "/api/v1/members/"+url.PathEscape(memberID)+"/notifications", nil, nil, &preferences); err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, memberID string, preferences NotificationPreference) (*NotificationPreference, error) {
+	var updated NotificationPreference
+	if err := c.do(ctx, http.MethodPut, "/api/v1/members/"+url.PathEscape(memberID)+"/notifications", nil, preferences, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) ExportMember(ctx context.Context, id string) (*MemberExport, error) {
+	var export MemberExport
+	if err := c.do(ctx, http.MethodGet, "/api/v1/members/"+url.PathEscape(id)+"/export", nil, nil, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (c *Client) EraseMember(ctx context.Context, id string) (*ErasureReceipt, error) {
+	var receipt ErasureReceipt
+	if err := c.do(ctx, http.MethodPost, "/api/v1/members/"+url.PathEscape(id)+"/erase", nil, nil, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (c *Client) SetMemberLegalHold(ctx context.Context, id string, legalHold bool) (*TeamMember, error) {
+	var member TeamMember
+	if err := c.do(ctx, http.MethodPut, "/api/v1/members/"+url.PathEscape(id)+"/legal-hold", nil, LegalHoldRequest{LegalHold: legalHold}, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (c *Client) UploadMemberAvatar(ctx context.Context, id, filename string, image []byte) (*TeamMember, error) {
+	var member TeamMember
+	if err := c.upload(ctx, "/api/v1/members/"+url.PathEscape(id)+"/avatar", filename, image, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (c *Client) DeleteMemberAvatar(ctx context.Context, id string) (*TeamMember, error) {
+	var member TeamMember
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/members/"+url.PathEscape(id)+"/avatar", nil, nil, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type ReminderFilter struct {
+	Status     string
+	TargetType string
+}
+
+func (c *Client) ListReminders(ctx context.Context, filter ReminderFilter) ([]Reminder, error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query.Set("target_type", filter.TargetType)
+	}
+
+	var reminders []Reminder
+	err := c.do(ctx, http.MethodGet, "/api/v1/reminders", query, nil, &reminders)
+	return reminders, err
+}
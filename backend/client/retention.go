@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) GetRetentionReport(ctx context.Context) (*RetentionReport, error) {
+	var report RetentionReport
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/retention", nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *Client) RunRetention(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	query := url.Values{}
+	query.Set("dry_run", strconv.FormatBool(dryRun))
+
+	var report RetentionReport
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/retention/run", query, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type AssignRequest struct {
+	MemberID       string  `json:"member_id"`
+	TeamID         string  `json:"team_id"`
+	ExpectedTeamID *string `json:"expected_team_id,omitempty"`
+}
+
+func (c *Client) CreateTeam(ctx context.Context, team Team) (*Team, error) {
+	var created Team
+	if err := c.do(ctx, http.MethodPost, "/api/v1/teams", nil, team, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) ListTeams(ctx context.Context) ([]Team, error) {
+	var teams []Team
+	err := c.do(ctx, http.MethodGet, "/api/v1/teams", nil, nil, &teams)
+	return teams, err
+}
+
+func (c *Client) GetTeam(ctx context.Context, id string) (*Team, error) {
+	var team Team
+	if err := c.do(ctx, http.MethodGet, "/api/v1/teams/"+url.PathEscape(id), nil, nil, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (c *Client) UpdateTeam(ctx context.Context, id string, team Team) (*Team, error) {
+	var updated Team
+	if err := c.do(ctx, http.MethodPut, "/api/v1/teams/"+url.PathEscape(id), nil, team, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteTeam(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/teams/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c *Client) AssignMember(ctx context.Context, req AssignRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/teams/assign", nil, req, nil)
+}
+
+func (c *Client) RemoveMember(ctx context.Context, memberID string, expectedTeamID *string) error {
+	query := url.Values{}
+	if expectedTeamID != nil {
+		query.Set("expected_team_id", *expectedTeamID)
+	}
+	return c.do(ctx, http.MethodDelete, "/api/v1/teams/members/"+url.PathEscape(memberID), query, nil, nil)
+}
+
+func (c *Client) UploadTeamLogo(ctx context.Context, id, filename string, image []byte) (*Team, error) {
+	var team Team
+	if err := c.upload(ctx, "/api/v1/teams/"+url.PathEscape(id)+"/logo", filename, image, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (c *Client) DeleteTeamLogo(ctx context.Context, id string) (*Team, error) {
+	var team Team
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/teams/"+url.PathEscape(id)+"/logo", nil, nil, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
@@ -0,0 +1,159 @@
+package client
+
+import "time"
+
+type Skill struct {
+	Name        string `json:"name"`
+	Proficiency int    `json:"proficiency"`
+}
+
+type TeamMember struct {
+	ID                  string     `json:"id"`
+	Name                string     `json:"name"`
+	Picture             string     `json:"picture"`
+	Email               string     `json:"email"`
+	TeamID              *string    `json:"team_id"`
+	Title               string     `json:"title"`
+	Level               string     `json:"level"`
+	Location            string     `json:"location"`
+	TimeZone            string     `json:"time_zone"`
+	StartDate           string     `json:"start_date"`
+	Skills              []Skill    `json:"skills"`
+	ProfileCompleteness int        `json:"profile_completeness"`
+	LegalHold           bool       `json:"legal_hold"`
+	ErasedAt            *time.Time `json:"erased_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type Team struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Logo      string       `json:"logo"`
+	Members   []TeamMember `json:"members"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type Feedback struct {
+	ID         string    `json:"id"`
+	Content    string    `json:"content"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	TargetName string    `json:"target_name"`
+	AuthorID   *string   `json:"author_id"`
+	GoalID     *string   `json:"goal_id"`
+	Category   string    `json:"category"`
+	LegalHold  bool      `json:"legal_hold"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Goal struct {
+	ID          string    `json:"id"`
+	OwnerType   string    `json:"owner_type"`
+	OwnerID     string    `json:"owner_id"`
+	OwnerName   string    `json:"owner_name"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	TargetDate  string    `json:"target_date"`
+	Status      string    `json:"status"`
+	Progress    int       `json:"progress"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Reminder struct {
+	ID             string     `json:"id"`
+	TargetType     string     `json:"target_type"`
+	TargetID       string     `json:"target_id"`
+	TargetName     string     `json:"target_name"`
+	LastFeedbackAt *time.Time `json:"last_feedback_at"`
+	Status         string     `json:"status"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    *bool     `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type NotificationPreference struct {
+	MemberID           string    `json:"member_id"`
+	EmailOptOut        bool      `json:"email_opt_out"`
+	TeamFeedbackOptOut bool      `json:"team_feedback_opt_out"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type AuditEntry struct {
+	ID         string            `json:"id"`
+	Action     string            `json:"action"`
+	EntityType string            `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	Details    map[string]string `json:"details,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+type MemberExport struct {
+	ExportedAt             time.Time               `json:"exported_at"`
+	Member                 TeamMember              `json:"member"`
+	Team                   *Team                   `json:"team"`
+	Memberships            []AuditEntry            `json:"memberships"`
+	FeedbackReceived       []Feedback              `json:"feedback_received"`
+	FeedbackGiven          []Feedback              `json:"feedback_given"`
+	Goals                  []Goal                  `json:"goals"`
+	NotificationPreference *NotificationPreference `json:"notification_preference"`
+	AuditEntries           []AuditEntry            `json:"audit_entries"`
+}
+
+type ErasureReceipt struct {
+	ID                      string    `json:"id"`
+	MemberID                string    `json:"member_id"`
+	ErasedAt                time.Time `json:"erased_at"`
+	FeedbackAnonymized      int64     `json:"feedback_anonymized"`
+	FeedbackRelabelled      int64     `json:"feedback_relabelled"`
+	RemindersRelabelled     int64     `json:"reminders_relabelled"`
+	GoalsRelabelled         int64     `json:"goals_relabelled"`
+	AuditEntriesScrubbed    int64     `json:"audit_entries_scrubbed"`
+	WebhookPayloadsScrubbed int64     `json:"webhook_payloads_scrubbed"`
+	StreamEventsScrubbed    int64     `json:"stream_events_scrubbed"`
+	Signature               string    `json:"signature,omitempty"`
+}
+
+type RetentionPolicyReport struct {
+	Policy  string    `json:"policy"`
+	Cutoff  time.Time `json:"cutoff"`
+	Expired int64     `json:"expired"`
+	Held    int64     `json:"held"`
+	Purged  int64     `json:"purged"`
+}
+
+type RetentionReport struct {
+	DryRun   bool                    `json:"dry_run"`
+	RanAt    time.Time               `json:"ran_at"`
+	Policies []RetentionPolicyReport `json:"policies"`
+}
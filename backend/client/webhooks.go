@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func (c *Client) CreateWebhook(ctx context.Context, subscription WebhookSubscription) (*WebhookSubscription, error) {
+	var created WebhookSubscription
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhooks", nil, subscription, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookSubscription, error) {
+	var subscriptions []WebhookSubscription
+	err := c.do(ctx, http.MethodGet, "/api/v1/webhooks", nil, nil, &subscriptions)
+	return subscriptions, err
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id string) (*WebhookSubscription, error) {
+	var subscription WebhookSubscription
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks/"+url.PathEscape(id), nil, nil, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id string, subscription WebhookSubscription) (*WebhookSubscription, error) {
+	var updated WebhookSubscription
+	if err := c.do(ctx, http.MethodPut, "/api/v1/webhooks/"+url.PathEscape(id), nil, subscription, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id string) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := c.do(ctx, http.MethodGet, "/api/v1/webhooks/"+url.PathEscape(id)+"/deliveries", nil, nil, &deliveries)
+	return deliveries, err
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"coaching-backend/client"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (a *app) flags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("coachctl "+name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
+
+func (a *app) resolveTeam(ctx context.Context, ref string) (*client.Team, error) {
+	teams, err := a.api.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		if team.ID == ref || strings.EqualFold(team.Name, ref) {
+			return &team, nil
+		}
+	}
+	return nil, fmt.Errorf("team %q not found", ref)
+}
+
+func (a *app) resolveMembers(ctx context.Context, refs []string) ([]client.TeamMember, error) {
+	members, err := a.api.ListMembers(ctx, client.MemberFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []client.TeamMember
+	var missing []string
+	for _, ref := range refs {
+		found := false
+		for _, member := range members {
+			if member.ID == ref || strings.EqualFold(member.Email, ref) {
+				resolved = append(resolved, member)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, ref)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("members not found: %s", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+func (a *app) teamsList(ctx context.Context, args []string) error {
+	if err := a.flags("teams list").Parse(args); err != nil {
+		return err
+	}
+	teams, err := a.api.ListTeams(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(teams))
+	for _, team := range teams {
+		rows = append(rows, []string{team.ID, team.Name, strconv.Itoa(len(team.Members)), formatTime(team.CreatedAt)})
+	}
+	return a.printer.print(teams, []string{"ID", "NAME", "MEMBERS", "CREATED"}, rows)
+}
+
+func (a *app) teamsCreate(ctx context.Context, args []string) error {
+	flags := a.flags("teams create")
+	name := flags.String("name", "", "team name")
+	logo := flags.String("logo", "", "logo URL")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	team, err := a.api.CreateTeam(ctx, client.Team{Name: *name, Logo: *logo})
+	if err != nil {
+		return err
+	}
+	return a.printer.print(team, []string{"ID", "NAME"}, [][]string{{team.ID, team.Name}})
+}
+
+func (a *app) teamsDelete(ctx context.Context, args []string) error {
+	flags := a.flags("teams delete")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("at least one team is required")
+	}
+
+	for _, ref := range flags.Args() {
+		team, err := a.resolveTeam(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if err := a.api.DeleteTeam(ctx, team.ID); err != nil {
+			return fmt.Errorf("delete team %s: %w", team.Name, err)
+		}
+		fmt.Fprintf(a.stderr, "Deleted team %s (%s)\n", team.Name, team.ID)
+	}
+	return nil
+}
+
+func (a *app) membersList(ctx context.Context, args []string) error {
+	flags := a.flags("members list")
+	teamRef := flags.String("team", "", "only members of this team")
+	unassigned := flags.Bool("unassigned", false, "only members without a team")
+	skill := flags.String("skill", "", "only members with this skill")
+	minProficiency := flags.Int("min-proficiency", 0, "minimum proficiency (1-5) for --skill")
+	level := flags.String("level", "", "only members at this level")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	teams, err := a.api.ListTeams(ctx)
+	if err != nil {
+		return err
+	}
+	teamNames := map[string]string{}
+	var teamID string
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+		if *teamRef != "" && (team.ID == *teamRef || strings.EqualFold(team.Name, *teamRef)) {
+			teamID = team.ID
+		}
+	}
+	if *teamRef != "" && teamID == "" {
+		return fmt.Errorf("team %q not found", *teamRef)
+	}
+
+	members, err := a.api.ListMembers(ctx, client.MemberFilter{Skill: *skill, MinProficiency: *minProficiency, Level: *level})
+	if err != nil {
+		return err
+	}
+	filtered := []client.TeamMember{}
+	rows := [][]string{}
+	for _, member := range members {
+		if *unassigned && member.TeamID != nil {
+			continue
+		}
+		if teamID != "" && (member.TeamID == nil || *member.TeamID != teamID) {
+			continue
+		}
+		team := ""
+		if member.TeamID != nil {
+			team = teamNames[*member.TeamID]
+		}
+		filtered = append(filtered, member)
+		rows = append(rows, []string{member.ID, member.Name, member.Email, team})
+	}
+	return a.printer.print(filtered, []string{"ID", "NAME", "EMAIL", "TEAM"}, rows)
+}
+
+func (a *app) membersAdd(ctx context.Context, args []string) error {
+	flags := a.flags("members add")
+	name := flags.String("name", "", "member name")
+	email := flags.String("email", "", "member email")
+	picture := flags.String("picture", "", "picture URL")
+	teamRef := flags.String("team", "", "team to assign the new member to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" || *email == "" {
+		return fmt.Errorf("--name and --email are required")
+	}
+
+	var team *client.Team
+	if *teamRef != "" {
+		resolved, err := a.resolveTeam(ctx, *teamRef)
+		if err != nil {
+			return err
+		}
+		team = resolved
+	}
+
+	member, err := a.api.CreateMember(ctx, client.TeamMember{Name: *name, Email: *email, Picture: *picture})
+	if err != nil {
+		return err
+	}
+	teamName := ""
+	if team != nil {
+		if err := a.api.AssignMember(ctx, client.AssignRequest{MemberID: member.ID, TeamID: team.ID}); err != nil {
+			return fmt.Errorf("member %s created but not assigned: %w", member.ID, err)
+		}
+		member.TeamID = &team.ID
+		teamName = team.Name
+	}
+	return a.printer.print(member, []string{"ID", "NAME", "EMAIL", "TEAM"}, [][]string{{member.ID, member.Name, member.Email, teamName}})
+}
+
+type assignResult struct {
+	MemberID string `json:"member_id"`
+	Email    string `json:"email"`
+	TeamID   string `json:"team_id,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (a *app) printResults(results []assignResult) error {
+	failed := 0
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		rows = append(rows, []string{result.MemberID, result.Email, result.Status, result.Error})
+	}
+	if err := a.printer.print(results, []string{"MEMBER", "EMAIL", "STATUS", "ERROR"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d members failed", failed, len(results))
+	}
+	return nil
+}
+
+func (a *app) membersAssign(ctx context.Context, args []string) error {
+	flags := a.flags("members assign")
+	teamRef := flags.String("team", "", "team to assign the members to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *teamRef == "" || flags.NArg() == 0 {
+		return fmt.Errorf("--team and at least one member are required")
+	}
+
+	team, err := a.resolveTeam(ctx, *teamRef)
+	if err != nil {
+		return err
+	}
+	members, err := a.resolveMembers(ctx, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	results := make([]assignResult, 0, len(members))
+	for _, member := range members {
+		result := assignResult{MemberID: member.ID, Email: member.Email, TeamID: team.ID, Status: "assigned"}
+		if member.TeamID != nil && *member.TeamID == team.ID {
+			result.Status = "unchanged"
+		} else if err := a.api.AssignMember(ctx, client.AssignRequest{MemberID: member.ID, TeamID: team.ID, ExpectedTeamID: expectedTeam(member)}); err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return a.printResults(results)
+}
+
+func (a *app) membersUnassign(ctx context.Context, args []string) error {
+	flags := a.flags("members unassign")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("at least one member is required")
+	}
+
+	members, err := a.resolveMembers(ctx, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	results := make([]assignResult, 0, len(members))
+	for _, member := range members {
+		result := assignResult{MemberID: member.ID, Email: member.Email, Status: "unassigned"}
+		if member.TeamID == nil {
+			result.Status = "unchanged"
+		} else if err := a.api.RemoveMember(ctx, member.ID, member.TeamID); err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return a.printResults(results)
+}
+
+func expectedTeam(member client.TeamMember) *string {
+	if member.TeamID == nil {
+		empty := ""
+		return &empty
+	}
+	return member.TeamID
+}
+
+func (a *app) feedbackFilter(ctx context.Context, targetType, target string) (client.FeedbackFilter, error) {
+	filter := client.FeedbackFilter{TargetType: targetType}
+	if target == "" {
+		return filter, nil
+	}
+
+	if targetType != "member" {
+		if team, err := a.resolveTeam(ctx, target); err == nil {
+			filter.TargetType, filter.TargetID = "team", team.ID
+			return filter, nil
+		} else if targetType == "team" {
+			return filter, err
+		}
+	}
+	members, err := a.resolveMembers(ctx, []string{target})
+	if err != nil {
+		return filter, fmt.Errorf("target %q not found", target)
+	}
+	filter.TargetType, filter.TargetID = "member", members[0].ID
+	return filter, nil
+}
+
+func (a *app) feedbackList(ctx context.Context, args []string) error {
+	flags := a.flags("feedback list")
+	targetType := flags.String("target-type", "", "only feedback for teams or members")
+	target := flags.String("target", "", "only feedback for this team or member")
+	search := flags.String("query", "", "only feedback containing every word of this query")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := a.feedbackFilter(ctx, *targetType, *target)
+	if err != nil {
+		return err
+	}
+	filter.Query = *search
+	feedbacks, err := a.api.ListFeedbacks(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(feedbacks))
+	for _, feedback := range feedbacks {
+		rows = append(rows, []string{feedback.ID, feedback.TargetType, feedback.TargetName, truncate(feedback.Content, 60), formatTime(feedback.CreatedAt)})
+	}
+	return a.printer.print(feedbacks, []string{"ID", "TYPE", "TARGET", "CONTENT", "CREATED"}, rows)
+}
+
+func (a *app) feedbackExport(ctx context.Context, args []string) error {
+	flags := a.flags("feedback export")
+	targetType := flags.String("target-type", "", "only feedback for teams or members")
+	target := flags.String("target", "", "only feedback for this team or member")
+	format := flags.String("format", "csv", "export format: csv or json")
+	out := flags.String("out", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("format must be either 'csv' or 'json'")
+	}
+
+	filter, err := a.feedbackFilter(ctx, *targetType, *target)
+	if err != nil {
+		return err
+	}
+	feedbacks, err := a.api.ListFeedbacks(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	w := a.stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := writeFeedback(w, *format, feedbacks); err != nil {
+		return err
+	}
+	if *out != "" {
+		fmt.Fprintf(a.stderr, "Exported %d feedback entries to %s\n", len(feedbacks), *out)
+	}
+	return nil
+}
+
+func writeFeedback(w io.Writer, format string, feedbacks []client.Feedback) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(feedbacks)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "target_type", "target_id", "target_name", "content", "created_at", "updated_at"})
+	for _, feedback := range feedbacks {
+		writer.Write([]string{
+			feedback.ID,
+			feedback.TargetType,
+			feedback.TargetID,
+			feedback.TargetName,
+			feedback.Content,
+			feedback.CreatedAt.UTC().Format(time.RFC3339),
+			feedback.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
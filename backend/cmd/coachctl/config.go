@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type config struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token"`
+	Output string `yaml:"output"`
+}
+
+func defaultConfigPath() string {
+	if path := os.Getenv("COACHCTL_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "coachctl", "config.yaml")
+}
+
+func loadConfig(path string, explicit bool) (config, error) {
+	cfg := config{Server: "http://localhost:8080", Output: "table"}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("parse %s: %w", path, err)
+			}
+		case errors.Is(err, fs.ErrNotExist) && !explicit:
+		default:
+			return cfg, err
+		}
+	}
+
+	if server := os.Getenv("COACHCTL_SERVER"); server != "" {
+		cfg.Server = server
+	}
+	if token := os.Getenv("COACHCTL_TOKEN"); token != "" {
+		cfg.Token = token
+	}
+	return cfg, nil
+}
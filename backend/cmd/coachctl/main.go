@@ -0,0 +1,122 @@
+package main
+
+import (
+	"coaching-backend/client"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: coachctl [flags] <command> <subcommand> [args]
+
+Commands:
+  teams list
+  teams create --name NAME [--logo URL]
+  teams delete TEAM...
+  members list [--team TEAM] [--unassigned] [--skill SKILL [--min-proficiency N]] [--level LEVEL]
+  members add --name NAME --email EMAIL [--picture URL] [--team TEAM]
+  members assign --team TEAM MEMBER...
+  members unassign MEMBER...
+  feedback list [--target-type team|member] [--target TEAM|MEMBER] [--query TEXT]
+  feedback export [--target-type team|member] [--target TEAM|MEMBER] [--format csv|json] [--out FILE]
+
+Teams can be given by ID or name, members by ID or email.
+
+Flags:
+`
+
+type app struct {
+	api     *client.Client
+	printer printer
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "coachctl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("coachctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "config file (default "+defaultConfigPath()+")")
+	server := flags.String("server", "", "server URL, overrides the config file")
+	token := flags.String("token", "", "API token, overrides the config file")
+	output := flags.String("output", "", "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	path := *configPath
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadConfig(path, *configPath != "")
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if *token != "" {
+		cfg.Token = *token
+	}
+	if *output != "" {
+		cfg.Output = *output
+	}
+	if cfg.Output != "table" && cfg.Output != "json" {
+		return fmt.Errorf("output must be either 'table' or 'json'")
+	}
+
+	rest := flags.Args()
+	if len(rest) < 2 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	a := &app{
+		api:     client.New(cfg.Server, client.WithToken(cfg.Token)),
+		printer: printer{out: stdout, format: cfg.Output},
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+
+	command, subcommand, args := rest[0], rest[1], rest[2:]
+	switch command + " " + subcommand {
+	case "teams list":
+		return a.teamsList(ctx, args)
+	case "teams create":
+		return a.teamsCreate(ctx, args)
+	case "teams delete":
+		return a.teamsDelete(ctx, args)
+	case "members list":
+		return a.membersList(ctx, args)
+	case "members add":
+		return a.membersAdd(ctx, args)
+	case "members assign":
+		return a.membersAssign(ctx, args)
+	case "members unassign":
+		return a.membersUnassign(ctx, args)
+	case "feedback list":
+		return a.feedbackList(ctx, args)
+	case "feedback export":
+		return a.feedbackExport(ctx, args)
+	}
+	return fmt.Errorf("unknown command %q, run coachctl -h for usage", command+" "+subcommand)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"coaching-backend/routes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCoachctlWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, models.AutoMigrate(db))
+	database.DB = db
+
+	r := gin.New()
+	routes.Register(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("server: "+server.URL+"\ntoken: secret\n"), 0o600))
+
+	coachctl := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		err := run(context.Background(), append([]string{"--config", configPath}, args...), &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	_, err = coachctl("teams", "create", "--name", "Platform")
+	require.NoError(t, err)
+	_, err = coachctl("members", "add", "--name", "Ada", "--email", "ada@example.com")
+	require.NoError(t, err)
+	_, err = coachctl("members", "add", "--name", "Grace", "--email", "grace@example.com", "--team", "platform")
+	require.NoError(t, err)
+
+	out, err := coachctl("members", "assign", "--team", "Platform", "ada@example.com", "grace@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, out, "assigned")
+	assert.Contains(t, out, "unchanged")
+
+	out, err = coachctl("--output", "json", "teams", "list")
+	require.NoError(t, err)
+	var teams []models.Team
+	require.NoError(t, json.Unmarshal([]byte(out), &teams))
+	require.Len(t, teams, 1)
+	assert.Len(t, teams[0].Members, 2)
+
+	_, err = coachctl("members", "assign", "--team", "Platform", "nobody@example.com")
+	assert.ErrorContains(t, err, "nobody@example.com")
+
+	_, err = coachctl("members", "unassign", "ada@example.com")
+	require.NoError(t, err)
+	out, err = coachctl("members", "list", "--unassigned")
+	require.NoError(t, err)
+	assert.Contains(t, out, "ada@example.com")
+	assert.NotContains(t, out, "grace@example.com")
+
+	var grace models.TeamMember
+	require.NoError(t, db.First(&grace, "email = ?", "grace@example.com").Error)
+	require.NoError(t, db.Create(&models.Feedback{ID: "f1", Content: "Great, \"clear\" demo", TargetType: "member", TargetID: grace.ID, TargetName: "Grace"}).Error)
+
+	out, err = coachctl("feedback", "list", "--target", "grace@example.com")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Grace")
+
+	out, err = coachctl("feedback", "export", "--target-type", "member")
+	require.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Great, \"clear\" demo", records[1][4])
+
+	_, err = coachctl("teams", "delete", "Platform")
+	require.NoError(t, err)
+	out, err = coachctl("teams", "list")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "Platform")
+}
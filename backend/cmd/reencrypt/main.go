@@ -0,0 +1,95 @@
+package main
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/logging"
+	"coaching-backend/services"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: reencrypt [flags] [-- server flags]
+
+Re-encrypts feedback content that is stored in plaintext or under a key other
+than the active key of ENCRYPTION_KEY_FILE, and rebuilds its search index
+entries. Server configuration is read the same way as the server does: from
+CONFIG_FILE, the environment, or the server flags given after --.
+
+Flags:
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "reencrypt:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("reencrypt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	batchSize := flags.Int("batch-size", 500, "rows read and rewritten per transaction")
+	reindex := flags.Bool("reindex", false, "rebuild the search index for every row, not only re-encrypted ones")
+	dryRun := flags.Bool("dry-run", false, "only report how many rows would be rewritten")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *batchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+
+	cfg, err := config.Load(flags.Args(), stderr)
+	if err != nil {
+		return err
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		return err
+	}
+	if err := encryption.Setup(cfg.Encryption); err != nil {
+		return fmt.Errorf("load encryption keys: %w", err)
+	}
+
+	database.Connect(cfg.Database, cfg.Env)
+	defer database.Close()
+
+	result, serviceErr := services.ReencryptFeedbacks(ctx, *batchSize, *reindex, *dryRun)
+	if serviceErr != nil {
+		return commandError(serviceErr)
+	}
+
+	verb := "re-encrypted"
+	if *dryRun {
+		verb = "would re-encrypt"
+	}
+	fmt.Fprintf(stdout, "scanned %d feedbacks, %s %d with key %q, reindexed %d\n", result.Scanned, verb, result.Reencrypted, encryption.ActiveKeyID(), result.Reindexed)
+
+	result, serviceErr = services.ReencryptWebhookDeliveries(ctx, *batchSize, *dryRun)
+	if serviceErr != nil {
+		return commandError(serviceErr)
+	}
+	fmt.Fprintf(stdout, "scanned %d webhook deliveries, %s %d with key %q\n", result.Scanned, verb, result.Reencrypted, encryption.ActiveKeyID())
+	return nil
+}
+
+func commandError(serviceErr *services.Error) error {
+	if serviceErr.Err != nil {
+		return fmt.Errorf("%s: %w", serviceErr.Message, serviceErr.Err)
+	}
+	return errors.New(serviceErr.Message)
+}
@@ -0,0 +1,399 @@
+package config
+
+import (
+	"coaching-backend/ratelimit"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Env           string        `yaml:"env" env:"APP_ENV" usage:"Deployment environment: development, test, staging or production"`
+	GinMode       string        `yaml:"gin_mode" env:"GIN_MODE" usage:"Gin mode: debug, release or test"`
+	HTTP          HTTP          `yaml:"http"`
+	GRPC          GRPC          `yaml:"grpc"`
+	Database      Database      `yaml:"database"`
+	CORS          CORS          `yaml:"cors"`
+	RateLimit     RateLimit     `yaml:"rate_limit"`
+	Log           Log           `yaml:"log"`
+	Tracing       Tracing       `yaml:"tracing"`
+	Health        Health        `yaml:"health"`
+	Reminders     Reminders     `yaml:"reminders"`
+	Retention     Retention     `yaml:"retention"`
+	Webhooks      Webhooks      `yaml:"webhooks"`
+	Notifications Notifications `yaml:"notifications"`
+	Signing       Signing       `yaml:"signing"`
+	Encryption    Encryption    `yaml:"encryption"`
+	Media         Media         `yaml:"media"`
+}
+
+type HTTP struct {
+	Port              int           `yaml:"port" env:"PORT" usage:"HTTP listen port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" usage:"Maximum time to read a request"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" usage:"Maximum time to read request headers"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" usage:"Maximum time to write a response"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" usage:"Keep-alive idle timeout"`
+	DrainDelay        time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY" usage:"Time to keep serving after failing readiness on shutdown"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" usage:"Upper bound for draining requests and stopping workers"`
+	TrustedProxies    []string      `yaml:"trusted_proxies" env:"TRUSTED_PROXIES" usage:"Comma separated proxy IPs or CIDRs whose X-Forwarded-For is trusted"`
+}
+
+type GRPC struct {
+	Port int `yaml:"port" env:"GRPC_PORT" usage:"gRPC listen port"`
+}
+
+type Database struct {
+	DSN               string        `yaml:"dsn" env:"DB_DSN" secret:"dsn" usage:"MySQL data source name"`
+	DSNFile           string        `yaml:"dsn_file" env:"DB_DSN_FILE" secret_file:"dsn" usage:"File containing the data source name, overrides dsn"`
+	DSNReloadInterval time.Duration `yaml:"dsn_reload_interval" env:"DB_DSN_RELOAD_INTERVAL" usage:"How often dsn_file is checked for rotated credentials, 0 to disable"`
+	MaxOpenConns      int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" usage:"Maximum open connections, 0 for unlimited"`
+	MaxIdleConns      int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" usage:"Maximum idle connections"`
+	ConnMaxLifetime   time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" usage:"Maximum connection lifetime, 0 for unlimited"`
+	ConnMaxIdleTime   time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" usage:"Maximum connection idle time, 0 for unlimited"`
+}
+
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" usage:"Comma separated origins allowed for CORS and WebSockets, * for any"`
+}
+
+type RateLimit struct {
+	Enabled bool            `yaml:"enabled" env:"RATE_LIMIT_ENABLED" usage:"Enable per-client rate limiting"`
+	Read    ratelimit.Limit `yaml:"read" env:"RATE_LIMIT_READ" usage:"Default limit for GET requests, requests/window or off"`
+	Write   ratelimit.Limit `yaml:"write" env:"RATE_LIMIT_WRITE" usage:"Default limit for POST, PUT, PATCH and DELETE requests"`
+	Routes  []string        `yaml:"routes" env:"RATE_LIMIT_ROUTES" usage:"Comma separated per-route limits such as 'POST /api/v1/feedbacks=20/1m'"`
+}
+
+type Log struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" usage:"Log level: debug, info, warn or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" usage:"Log format: json or text"`
+}
+
+type Tracing struct {
+	Exporter       string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" usage:"Trace exporter: otlp, stdout or none"`
+	Endpoint       string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" usage:"OTLP/HTTP collector base URL"`
+	TracesEndpoint string `yaml:"traces_endpoint" env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT" usage:"OTLP/HTTP traces URL, overrides endpoint"`
+	ServiceName    string `yaml:"service_name" env:"OTEL_SERVICE_NAME" usage:"Service name reported on spans"`
+}
+
+type Health struct {
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" env:"READINESS_TIMEOUT" usage:"Timeout for readiness checks"`
+	PoolSaturation   float64       `yaml:"pool_saturation" env:"READINESS_POOL_SATURATION" usage:"Pool saturation (0-1] reported as a warning"`
+}
+
+type Reminders struct {
+	Window       time.Duration `yaml:"window" env:"REMINDER_WINDOW" usage:"Targets without feedback for this long get a reminder"`
+	ScanInterval time.Duration `yaml:"scan_interval" env:"REMINDER_SCAN_INTERVAL" usage:"Time between reminder scans"`
+}
+
+type Retention struct {
+	Enabled   bool          `yaml:"enabled" env:"RETENTION_ENABLED" usage:"Delete feedback older than its retention policy"`
+	DryRun    bool          `yaml:"dry_run" env:"RETENTION_DRY_RUN" usage:"Log what the retention job would delete without deleting it"`
+	Interval  time.Duration `yaml:"interval" env:"RETENTION_INTERVAL" usage:"Time between retention runs"`
+	BatchSize int           `yaml:"batch_size" env:"RETENTION_BATCH_SIZE" usage:"Feedbacks deleted per transaction"`
+	Policies  []string      `yaml:"policies" env:"RETENTION_POLICIES" usage:"Comma separated maximum ages by target type and optional category, such as 'member=3y,team=5y,member/praise=1y'"`
+}
+
+type RetentionPolicy struct {
+	TargetType string
+	Category   string
+	MaxAge     time.Duration
+}
+
+type Webhooks struct {
+	PollInterval    time.Duration `yaml:"poll_interval" env:"WEBHOOK_POLL_INTERVAL" usage:"Time between webhook delivery polls"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" usage:"Delivery attempts before a webhook delivery fails"`
+	SubscriptionTTL time.Duration `yaml:"subscription_ttl" env:"WEBHOOK_SUBSCRIPTION_TTL" usage:"How long active webhook subscriptions are cached for new events"`
+	AllowedNetworks []string      `yaml:"allowed_networks" env:"WEBHOOK_ALLOWED_NETWORKS" usage:"Comma separated IPs or CIDRs of internal webhook receivers that may be delivered to"`
+}
+
+type Notifications struct {
+	Sender string `yaml:"sender" env:"NOTIFY_SENDER" usage:"Notification sender: stdout, file or smtp (default smtp when an SMTP host is set)"`
+	From   string `yaml:"from" env:"NOTIFY_FROM" usage:"Sender address for notification emails"`
+	File   string `yaml:"file" env:"NOTIFY_FILE" usage:"Output file for the file sender"`
+	SMTP   SMTP   `yaml:"smtp"`
+}
+
+type SMTP struct {
+	Host         string `yaml:"host" env:"SMTP_HOST" usage:"SMTP server host"`
+	Port         int    `yaml:"port" env:"SMTP_PORT" usage:"SMTP server port"`
+	Username     string `yaml:"username" env:"SMTP_USERNAME" usage:"SMTP username"`
+	Password     string `yaml:"password" env:"SMTP_PASSWORD" secret:"true" usage:"SMTP password"`
+	PasswordFile string `yaml:"password_file" env:"SMTP_PASSWORD_FILE" secret_file:"password" usage:"File containing the SMTP password, overrides password"`
+}
+
+type Signing struct {
+	Key     string `yaml:"key" env:"SIGNING_KEY" secret:"true" usage:"Server signing key, at least 32 characters"`
+	KeyFile string `yaml:"key_file" env:"SIGNING_KEY_FILE" secret_file:"key" usage:"File containing the signing key, overrides key"`
+}
+
+type Encryption struct {
+	KeyFile     string `yaml:"key_file" env:"ENCRYPTION_KEY_FILE" usage:"Keyring file used to encrypt feedback content, one key ID and base64 32-byte key per line, the last line is the active key"`
+	SearchIndex bool   `yaml:"search_index" env:"ENCRYPTION_SEARCH_INDEX" usage:"Maintain a keyed search index so feedback search keeps working while content is encrypted"`
+}
+
+type Media struct {
+	Dir            string `yaml:"dir" env:"MEDIA_DIR" usage:"Directory where uploaded avatars and logos are stored"`
+	BaseURL        string `yaml:"base_url" env:"MEDIA_BASE_URL" usage:"Public URL prefix for uploaded images, empty to use paths relative to this server"`
+	MaxUploadBytes int    `yaml:"max_upload_bytes" env:"MEDIA_MAX_UPLOAD_BYTES" usage:"Largest accepted image upload in bytes"`
+}
+
+const (
+	defaultDSN          = "root:password@tcp(localhost:3306)/coaching_app?charset=utf8mb4&parseTime=True&loc=Local"
+	minSigningKeyLength = 32
+)
+
+func Default() Config {
+	return Config{
+		Env:     "development",
+		GinMode: "release",
+		HTTP: HTTP{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		GRPC: GRPC{Port: 9090},
+		Database: Database{
+			DSN:               defaultDSN,
+			DSNReloadInterval: 30 * time.Second,
+			MaxOpenConns:      25,
+			MaxIdleConns:      10,
+			ConnMaxLifetime:   5 * time.Minute,
+			ConnMaxIdleTime:   30 * time.Second,
+		},
+		CORS: CORS{AllowedOrigins: []string{
+			"http://localhost:3000",
+			"http://localhost:5173",
+			"http://localhost:8080",
+		}},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Read:    ratelimit.Limit{Requests: 300, Per: time.Minute},
+			Write:   ratelimit.Limit{Requests: 60, Per: time.Minute},
+			Routes:  []string{"POST /api/v1/feedbacks=20/1m"},
+		},
+		Log:     Log{Level: "info", Format: "json"},
+		Tracing: Tracing{ServiceName: "coaching-backend"},
+		Health: Health{
+			ReadinessTimeout: 2 * time.Second,
+			PoolSaturation:   0.9,
+		},
+		Reminders: Reminders{
+			Window:       90 * 24 * time.Hour,
+			ScanInterval: time.Hour,
+		},
+		Retention: Retention{
+			Interval:  24 * time.Hour,
+			BatchSize: 500,
+			Policies:  []string{"member=3y", "team=5y"},
+		},
+		Webhooks: Webhooks{
+			PollInterval:    2 * time.Second,
+			MaxAttempts:     8,
+			SubscriptionTTL: 30 * time.Second,
+		},
+		Notifications: Notifications{
+			From: "coaching-app@localhost",
+			File: "notifications.log",
+			SMTP: SMTP{Port: 587},
+		},
+		Media: Media{
+			Dir:            "media",
+			MaxUploadBytes: 5 << 20,
+		},
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(oneOf(c.Env, "development", "test", "staging", "production"), "env", "must be development, test, staging or production, got %q", c.Env)
+	check(oneOf(c.GinMode, "debug", "release", "test"), "gin_mode", "must be debug, release or test, got %q", c.GinMode)
+
+	check(validPort(c.HTTP.Port), "http.port", "must be between 1 and 65535, got %d", c.HTTP.Port)
+	check(validPort(c.GRPC.Port), "grpc.port", "must be between 1 and 65535, got %d", c.GRPC.Port)
+	check(c.HTTP.Port != c.GRPC.Port, "grpc.port", "must differ from http.port")
+	check(c.HTTP.ReadTimeout >= 0, "http.read_timeout", "must not be negative")
+	check(c.HTTP.ReadHeaderTimeout >= 0, "http.read_header_timeout", "must not be negative")
+	check(c.HTTP.WriteTimeout >= 0, "http.write_timeout", "must not be negative")
+	check(c.HTTP.IdleTimeout >= 0, "http.idle_timeout", "must not be negative")
+	check(c.HTTP.DrainDelay >= 0, "http.shutdown_drain_delay", "must not be negative")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout", "must be positive")
+	for _, proxy := range c.HTTP.TrustedProxies {
+		check(validProxy(proxy), "http.trusted_proxies", "%q must be an IP address or CIDR", proxy)
+	}
+
+	check(c.Database.DSN != "", "database.dsn", "is required")
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns", "must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns", "must not be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns, "database.max_idle_conns", "must not exceed max_open_conns (%d)", c.Database.MaxOpenConns)
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime", "must not be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time", "must not be negative")
+	check(c.Database.DSNReloadInterval >= 0, "database.dsn_reload_interval", "must not be negative")
+	if c.Env == "production" {
+		check(c.Database.DSN != defaultDSN && !strings.HasPrefix(c.Database.DSN, "root:password@"), "database.dsn", "the built-in default credentials cannot be used in production, set DB_DSN or DB_DSN_FILE")
+		check(len(c.Signing.Key) >= minSigningKeyLength, "signing.key", "must be set to at least %d characters in production, set SIGNING_KEY or SIGNING_KEY_FILE", minSigningKeyLength)
+	}
+	check(c.Signing.Key == "" || len(c.Signing.Key) >= minSigningKeyLength, "signing.key", "must be at least %d characters", minSigningKeyLength)
+
+	check(!c.Encryption.SearchIndex || c.Encryption.KeyFile != "", "encryption.search_index", "requires encryption.key_file")
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(validOrigin(origin), "cors.allowed_origins", "%q must be * or a scheme and host such as https://app.example.com", origin)
+	}
+
+	for _, route := range c.RateLimit.Routes {
+		_, _, err := ratelimit.ParseRoute(route)
+		check(err == nil, "rate_limit.routes", "%v", err)
+	}
+
+	check(oneOf(strings.ToLower(c.Log.Level), "debug", "info", "warn", "warning", "error"), "log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	check(oneOf(c.Log.Format, "json", "text"), "log.format", "must be json or text, got %q", c.Log.Format)
+
+	check(oneOf(c.Tracing.Exporter, "", "otlp", "stdout", "console", "none"), "tracing.exporter", "must be otlp, stdout or none, got %q", c.Tracing.Exporter)
+	check(c.Tracing.Endpoint == "" || validURL(c.Tracing.Endpoint), "tracing.endpoint", "must be an http or https URL, got %q", c.Tracing.Endpoint)
+	check(c.Tracing.TracesEndpoint == "" || validURL(c.Tracing.TracesEndpoint), "tracing.traces_endpoint", "must be an http or https URL, got %q", c.Tracing.TracesEndpoint)
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+
+	check(c.Health.ReadinessTimeout > 0, "health.readiness_timeout", "must be positive")
+	check(c.Health.PoolSaturation > 0 && c.Health.PoolSaturation <= 1, "health.pool_saturation", "must be in (0, 1], got %g", c.Health.PoolSaturation)
+
+	check(c.Reminders.Window > 0, "reminders.window", "must be positive")
+	check(c.Reminders.ScanInterval > 0, "reminders.scan_interval", "must be positive")
+
+	check(c.Retention.Interval > 0, "retention.interval", "must be positive")
+	check(c.Retention.BatchSize > 0, "retention.batch_size", "must be at least 1")
+	seen := map[string]bool{}
+	for _, value := range c.Retention.Policies {
+		policy, err := ParseRetentionPolicy(value)
+		check(err == nil, "retention.policies", "%v", err)
+		check(err != nil || !seen[policy.Key()], "retention.policies", "duplicate policy for %s", policy.Key())
+		seen[policy.Key()] = true
+	}
+
+	check(c.Webhooks.PollInterval > 0, "webhooks.poll_interval", "must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts", "must be at least 1")
+	check(c.Webhooks.SubscriptionTTL > 0, "webhooks.subscription_ttl", "must be positive")
+	for _, network := range c.Webhooks.AllowedNetworks {
+		check(validProxy(network), "webhooks.allowed_networks", "%q must be an IP address or CIDR", network)
+	}
+
+	check(oneOf(c.Notifications.Sender, "", "stdout", "file", "smtp"), "notifications.sender", "must be stdout, file or smtp, got %q", c.Notifications.Sender)
+	check(strings.Contains(c.Notifications.From, "@"), "notifications.from", "must be an email address, got %q", c.Notifications.From)
+	check(c.Notifications.Sender != "file" || c.Notifications.File != "", "notifications.file", "is required for the file sender")
+	check(c.Notifications.Sender != "smtp" || c.Notifications.SMTP.Host != "", "notifications.smtp.host", "is required for the smtp sender")
+	check(validPort(c.Notifications.SMTP.Port), "notifications.smtp.port", "must be between 1 and 65535, got %d", c.Notifications.SMTP.Port)
+
+	check(c.Media.Dir != "", "media.dir", "is required")
+	check(c.Media.BaseURL == "" || validURL(c.Media.BaseURL), "media.base_url", "must be an http or https URL, got %q", c.Media.BaseURL)
+	check(c.Media.MaxUploadBytes > 0, "media.max_upload_bytes", "must be positive")
+
+	return errors.Join(errs...)
+}
+
+func ParseRetentionPolicy(value string) (RetentionPolicy, error) {
+	key, age, ok := strings.Cut(value, "=")
+	targetType, category, _ := strings.Cut(strings.TrimSpace(key), "/")
+	policy := RetentionPolicy{TargetType: strings.TrimSpace(targetType), Category: strings.ToLower(strings.TrimSpace(category))}
+	if !ok || !oneOf(policy.TargetType, "team", "member") {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q, expected team|member[/category]=age such as member=3y", value)
+	}
+
+	age = strings.TrimSpace(age)
+	var err error
+	switch {
+	case strings.HasSuffix(age, "y"):
+		var years int
+		years, err = strconv.Atoi(strings.TrimSuffix(age, "y"))
+		policy.MaxAge = time.Duration(years) * 365 * 24 * time.Hour
+	case strings.HasSuffix(age, "d"):
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(age, "d"))
+		policy.MaxAge = time.Duration(days) * 24 * time.Hour
+	default:
+		policy.MaxAge, err = time.ParseDuration(age)
+	}
+	if err != nil || policy.MaxAge <= 0 {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q, age must be a positive number of years (3y), days (90d) or a duration", value)
+	}
+	return policy, nil
+}
+
+func (p RetentionPolicy) Key() string {
+	if p.Category == "" {
+		return p.TargetType
+	}
+	return p.TargetType + "/" + p.Category
+}
+
+func (c Retention) ParsedPolicies() []RetentionPolicy {
+	policies := make([]RetentionPolicy, 0, len(c.Policies))
+	for _, value := range c.Policies {
+		if policy, err := ParseRetentionPolicy(value); err == nil {
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
+
+func (c Webhooks) ParsedAllowedNetworks() []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(c.AllowedNetworks))
+	for _, value := range c.AllowedNetworks {
+		if _, network, err := net.ParseCIDR(value); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		if ip := net.ParseIP(value); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return networks
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func validURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
+
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && validURL(origin) && (u.Path == "" || u.Path == "/") && u.RawQuery == ""
+}
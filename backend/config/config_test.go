@@ -0,0 +1,345 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultsAreValid(t *testing.T) {
+	loaded, err := load(nil, lookup(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("default configuration is invalid: %v", err)
+	}
+	if loaded.HTTP.Port != 8080 || loaded.GRPC.Port != 9090 {
+		t.Errorf("unexpected default ports %d and %d", loaded.HTTP.Port, loaded.GRPC.Port)
+	}
+	if loaded.Retention.Enabled {
+		t.Error("the retention job must be opt-in")
+	}
+	if len(loaded.Sources) != 0 {
+		t.Errorf("expected no overridden keys, got %v", loaded.Sources)
+	}
+}
+
+func TestPrecedenceFileEnvFlags(t *testing.T) {
+	path := writeFile(t, `
+http:
+  port: 8000
+  write_timeout: 1m
+database:
+  max_open_conns: 50
+cors:
+  allowed_origins:
+    - https://app.example.com
+log:
+  level: debug
+`)
+	env := map[string]string{
+		"CONFIG_FILE":       path,
+		"PORT":              "8100",
+		"DB_MAX_OPEN_CONNS": "40",
+	}
+
+	loaded, err := load([]string{"--port", "8200"}, lookup(env), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.HTTP.Port != 8200 {
+		t.Errorf("flag should win over env and file, got port %d", loaded.HTTP.Port)
+	}
+	if loaded.Database.MaxOpenConns != 40 {
+		t.Errorf("env should win over file, got max_open_conns %d", loaded.Database.MaxOpenConns)
+	}
+	if loaded.HTTP.WriteTimeout != time.Minute || loaded.Log.Level != "debug" {
+		t.Errorf("file values should apply, got write_timeout %s and log level %q", loaded.HTTP.WriteTimeout, loaded.Log.Level)
+	}
+	if len(loaded.CORS.AllowedOrigins) != 1 || loaded.CORS.AllowedOrigins[0] != "https://app.example.com" {
+		t.Errorf("unexpected CORS origins %v", loaded.CORS.AllowedOrigins)
+	}
+	if loaded.HTTP.ReadTimeout != 15*time.Second {
+		t.Errorf("unset keys should keep their defaults, got read_timeout %s", loaded.HTTP.ReadTimeout)
+	}
+
+	want := map[string]string{
+		"http.port":               SourceFlag,
+		"database.max_open_conns": SourceEnv,
+		"http.write_timeout":      SourceFile,
+		"cors.allowed_origins":    SourceFile,
+		"log.level":               SourceFile,
+	}
+	for key, source := range want {
+		if loaded.Sources[key] != source {
+			t.Errorf("source of %s: want %s, got %q", key, source, loaded.Sources[key])
+		}
+	}
+
+	loaded, err = load([]string{"--config", writeFile(t, "grpc:\n  port: 9500\n")}, lookup(env), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.GRPC.Port != 9500 || loaded.Database.MaxOpenConns != 40 {
+		t.Errorf("--config should replace CONFIG_FILE, got grpc port %d", loaded.GRPC.Port)
+	}
+}
+
+func TestEnvListsAndOrigins(t *testing.T) {
+	env := map[string]string{"CORS_ALLOWED_ORIGINS": "https://a.example.com, https://b.example.com,"}
+	loaded, err := load(nil, lookup(env), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(loaded.CORS.AllowedOrigins, " ") != "https://a.example.com https://b.example.com" {
+		t.Errorf("unexpected origins %v", loaded.CORS.AllowedOrigins)
+	}
+}
+
+func TestWebhookAllowedNetworks(t *testing.T) {
+	cfg := Webhooks{AllowedNetworks: []string{"10.1.0.0/16", "127.0.0.1", "fd00::1"}}
+	networks := cfg.ParsedAllowedNetworks()
+	if len(networks) != 3 {
+		t.Fatalf("expected 3 networks, got %v", networks)
+	}
+	for i, want := range []string{"10.1.0.0/16", "127.0.0.1/32", "fd00::1/128"} {
+		if networks[i].String() != want {
+			t.Errorf("network %d: got %s, want %s", i, networks[i], want)
+		}
+	}
+}
+
+func TestInvalidValuesAreReported(t *testing.T) {
+	env := map[string]string{
+		"DB_MAX_OPEN_CONNS": "lots",
+		"HTTP_READ_TIMEOUT": "15",
+	}
+	_, err := load(nil, lookup(env), io.Discard)
+	if err == nil {
+		t.Fatal("expected parse errors")
+	}
+	for _, want := range []string{
+		`database.max_open_conns (env DB_MAX_OPEN_CONNS): expected an integer, got "lots"`,
+		`http.read_timeout (env HTTP_READ_TIMEOUT): expected a duration such as 30s or 5m, got "15"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	env = map[string]string{
+		"PORT":                     "70000",
+		"GRPC_PORT":                "70000",
+		"LOG_FORMAT":               "xml",
+		"CORS_ALLOWED_ORIGINS":     "localhost:3000",
+		"NOTIFY_SENDER":            "smtp",
+		"DB_MAX_IDLE_CONNS":        "30",
+		"WEBHOOK_ALLOWED_NETWORKS": "10.0.0.0/8,intranet",
+	}
+	_, err = load(nil, lookup(env), io.Discard)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"http.port: must be between 1 and 65535, got 70000",
+		"grpc.port: must differ from http.port",
+		`log.format: must be json or text, got "xml"`,
+		`cors.allowed_origins: "localhost:3000" must be *`,
+		"notifications.smtp.host: is required for the smtp sender",
+		"database.max_idle_conns: must not exceed max_open_conns (25)",
+		`webhooks.allowed_networks: "intranet" must be an IP address or CIDR`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if _, err := load(nil, lookup(map[string]string{"CONFIG_FILE": writeFile(t, "http:\n  prot: 80\n")}), io.Discard); err == nil || !strings.Contains(err.Error(), "field prot not found") {
+		t.Errorf("unknown file keys should be rejected, got %v", err)
+	}
+	if _, err := load([]string{"--no-such-flag"}, lookup(nil), io.Discard); err == nil {
+		t.Error("unknown flags should be rejected")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	env := map[string]string{
+		"DB_DSN":        "app:s3cret@tcp(db:3306)/coaching_app?parseTime=True",
+		"SMTP_PASSWORD": "hunter2",
+	}
+	loaded, err := load(nil, lookup(env), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view := loaded.Redacted()
+	database := view["database"].(map[string]interface{})
+	if database["dsn"] != "app:[REDACTED]@tcp(db:3306)/coaching_app?parseTime=True" {
+		t.Errorf("DSN password not redacted: %v", database["dsn"])
+	}
+	for dsn, want := range map[string]string{
+		"user:p@ss@tcp(db:3306)/app":         "user:[REDACTED]@tcp(db:3306)/app",
+		"user:p@ss/word@tcp(db:3306)/app":    "user:[REDACTED]@tcp(db:3306)/app",
+		"user@tcp(db:3306)/app?x=a@b":        "user@tcp(db:3306)/app?x=a@b",
+		"user:secret@unix(/tmp/mysql.sock)/": "user:[REDACTED]@unix(/tmp/mysql.sock)/",
+	} {
+		if got := redactDSN(dsn); got != want {
+			t.Errorf("redactDSN(%q) = %q, want %q", dsn, got, want)
+		}
+	}
+	if database["conn_max_lifetime"] != "5m0s" {
+		t.Errorf("durations should be rendered as strings, got %v", database["conn_max_lifetime"])
+	}
+	smtp := view["notifications"].(map[string]interface{})["smtp"].(map[string]interface{})
+	if smtp["password"] != "[REDACTED]" {
+		t.Errorf("SMTP password not redacted: %v", smtp["password"])
+	}
+	if smtp["port"] != 587 {
+		t.Errorf("unexpected SMTP port %v", smtp["port"])
+	}
+	if strings.Contains(strings.Join([]string{database["dsn"].(string), smtp["password"].(string)}, " "), "s3cret") {
+		t.Error("secret leaked into the redacted view")
+	}
+}
+
+func TestRateLimitValues(t *testing.T) {
+	path := writeFile(t, "rate_limit:\n  read: 100/1m\n  routes:\n    - POST /api/v1/feedbacks=5/10s\n")
+	env := map[string]string{"CONFIG_FILE": path, "RATE_LIMIT_WRITE": "off"}
+	loaded, err := load(nil, lookup(env), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.RateLimit.Read.String() != "100/1m" || !loaded.RateLimit.Write.Unlimited() {
+		t.Errorf("unexpected limits %s and %s", loaded.RateLimit.Read, loaded.RateLimit.Write)
+	}
+	if view := loaded.Redacted()["rate_limit"].(map[string]interface{}); view["read"] != "100/1m" || view["write"] != "off" {
+		t.Errorf("limits should be rendered as strings, got %v", view)
+	}
+
+	env = map[string]string{"RATE_LIMIT_READ": "fast", "RATE_LIMIT_ROUTES": "/api/v1/feedbacks=5/1m"}
+	_, err = load(nil, lookup(env), io.Discard)
+	if err == nil {
+		t.Fatal("expected rate limit errors")
+	}
+	for _, want := range []string{"rate_limit.read (env RATE_LIMIT_READ)", "rate_limit.routes: invalid route limit"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	dsnFile := filepath.Join(dir, "db_dsn")
+	keyFile := filepath.Join(dir, "signing_key")
+	if err := os.WriteFile(dsnFile, []byte("app:rotated@tcp(db:3306)/coaching_app\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(strings.Repeat("k", 40)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"APP_ENV":          "production",
+		"DB_DSN":           "app:inline@tcp(db:3306)/coaching_app",
+		"DB_DSN_FILE":      dsnFile,
+		"SIGNING_KEY_FILE": keyFile,
+	}
+	loaded, err := load(nil, lookup(env), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Database.DSN != "app:rotated@tcp(db:3306)/coaching_app" {
+		t.Errorf("the DSN file should override DB_DSN, got %q", loaded.Database.DSN)
+	}
+	if loaded.Signing.Key != strings.Repeat("k", 40) {
+		t.Errorf("signing key not read from file")
+	}
+	if loaded.Sources["database.dsn"] != SourceSecret || loaded.Sources["database.dsn_file"] != SourceEnv {
+		t.Errorf("unexpected sources %v", loaded.Sources)
+	}
+	view := loaded.Redacted()
+	if view["signing"].(map[string]interface{})["key"] != "[REDACTED]" {
+		t.Error("signing key not redacted")
+	}
+
+	_, err = load(nil, lookup(map[string]string{"SMTP_PASSWORD_FILE": filepath.Join(dir, "missing")}), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "notifications.smtp.password_file: open") {
+		t.Errorf("a missing secret file should be reported, got %v", err)
+	}
+}
+
+func TestProductionRefusesDefaultCredentials(t *testing.T) {
+	_, err := load(nil, lookup(map[string]string{"APP_ENV": "production"}), io.Discard)
+	if err == nil {
+		t.Fatal("production must not start with the default DSN")
+	}
+	for _, want := range []string{
+		"database.dsn: the built-in default credentials cannot be used in production",
+		"signing.key: must be set to at least 32 characters in production",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	env := map[string]string{
+		"APP_ENV":     "production",
+		"DB_DSN":      "root:password@tcp(db:3306)/coaching_app",
+		"SIGNING_KEY": strings.Repeat("k", 32),
+	}
+	if _, err := load(nil, lookup(env), io.Discard); err == nil {
+		t.Error("the default root password must be refused for any host")
+	}
+
+	if _, err := load(nil, lookup(map[string]string{"SIGNING_KEY": "short"}), io.Discard); err == nil {
+		t.Error("short signing keys must be refused")
+	}
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	for value, want := range map[string]RetentionPolicy{
+		"member=3y":         {TargetType: "member", MaxAge: 3 * 365 * 24 * time.Hour},
+		" team = 90d ":      {TargetType: "team", MaxAge: 90 * 24 * time.Hour},
+		"member/Praise=36h": {TargetType: "member", Category: "praise", MaxAge: 36 * time.Hour},
+	} {
+		got, err := ParseRetentionPolicy(value)
+		if err != nil || got != want {
+			t.Errorf("ParseRetentionPolicy(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "member", "user=3y", "member=0y", "team=soon", "member/praise=-1d"} {
+		if _, err := ParseRetentionPolicy(value); err == nil {
+			t.Errorf("ParseRetentionPolicy(%q) should fail", value)
+		}
+	}
+
+	env := map[string]string{"RETENTION_POLICIES": "member=3y,team=forever,member=1y"}
+	_, err := load(nil, lookup(env), io.Discard)
+	if err == nil {
+		t.Fatal("expected retention policy errors")
+	}
+	for _, want := range []string{`retention.policies: invalid retention policy "team=forever"`, "retention.policies: duplicate policy for member"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+	SourceSecret  = "secret_file"
+
+	redacted = "[REDACTED]"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type field struct {
+	Key        string
+	Env        string
+	Flag       string
+	Usage      string
+	Secret     string
+	SecretFile string
+	Value      reflect.Value
+}
+
+func fields(cfg *Config) []field {
+	var result []field
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := prefix + sf.Tag.Get("yaml")
+			if sf.Type.Kind() == reflect.Struct && !reflect.PointerTo(sf.Type).Implements(textUnmarshalerType) {
+				walk(v.Field(i), key+".")
+				continue
+			}
+			env := sf.Tag.Get("env")
+			result = append(result, field{
+				Key:        key,
+				Env:        env,
+				Flag:       strings.ReplaceAll(strings.ToLower(env), "_", "-"),
+				Usage:      sf.Tag.Get("usage"),
+				Secret:     sf.Tag.Get("secret"),
+				SecretFile: prefixed(prefix, sf.Tag.Get("secret_file")),
+				Value:      v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return result
+}
+
+func (f field) set(raw string) error {
+	v := f.Value
+	if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("expected a duration such as 30s or 5m, got %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", raw)
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var values []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+type Loaded struct {
+	Config
+	Path    string
+	Sources map[string]string
+}
+
+func Load(args []string, output io.Writer) (*Loaded, error) {
+	return load(args, os.LookupEnv, output)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Loaded, error) {
+	loaded := &Loaded{Config: Default(), Sources: map[string]string{}}
+	all := fields(&loaded.Config)
+
+	flags := flag.NewFlagSet("coaching-backend", flag.ContinueOnError)
+	flags.SetOutput(output)
+	path := flags.String("config", "", "YAML configuration file (env CONFIG_FILE)")
+	flagValues := map[string]string{}
+	for _, f := range all {
+		name := f.Flag
+		flags.Func(name, f.Usage+" (env "+f.Env+")", func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	loaded.Path = *path
+	if loaded.Path == "" {
+		loaded.Path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if loaded.Path != "" {
+		if err := loaded.readFile(all); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, f := range all {
+		if raw, ok := lookupEnv(f.Env); ok && raw != "" {
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s (env %s): %w", f.Key, f.Env, err))
+				continue
+			}
+			loaded.Sources[f.Key] = SourceEnv
+		}
+	}
+	for _, f := range all {
+		if raw, ok := flagValues[f.Flag]; ok {
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s (flag --%s): %w", f.Key, f.Flag, err))
+				continue
+			}
+			loaded.Sources[f.Key] = SourceFlag
+		}
+	}
+	errs = append(errs, loaded.readSecretFiles(all)...)
+
+	if err := loaded.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return loaded, nil
+}
+
+func (l *Loaded) readFile(all []field) error {
+	data, err := os.ReadFile(l.Path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&l.Config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", l.Path, err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("parse config file %s: %w", l.Path, err)
+	}
+	present := map[string]bool{}
+	flattenKeys(raw, "", present)
+	for _, f := range all {
+		if present[f.Key] {
+			l.Sources[f.Key] = SourceFile
+		}
+	}
+	return nil
+}
+
+func ReadSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (l *Loaded) readSecretFiles(all []field) []error {
+	byKey := make(map[string]field, len(all))
+	for _, f := range all {
+		byKey[f.Key] = f
+	}
+
+	var errs []error
+	for _, f := range all {
+		path := f.Value.String()
+		if f.SecretFile == "" || path == "" {
+			continue
+		}
+		secret, err := ReadSecretFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Key, err))
+			continue
+		}
+		target := byKey[f.SecretFile]
+		target.Value.SetString(secret)
+		l.Sources[target.Key] = SourceSecret
+	}
+	return errs
+}
+
+func prefixed(prefix, key string) string {
+	if key == "" {
+		return ""
+	}
+	return prefix + key
+}
+
+func flattenKeys(values map[string]interface{}, prefix string, present map[string]bool) {
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenKeys(nested, prefix+key+".", present)
+			continue
+		}
+		present[prefix+key] = true
+	}
+}
+
+func redactDSN(dsn string) string {
+	end := len(dsn)
+	if slash := strings.LastIndex(dsn, "/"); slash >= 0 {
+		end = slash
+	}
+	at := strings.LastIndex(dsn[:end], "@")
+	if at < 0 {
+		return dsn
+	}
+	user, _, hasPassword := strings.Cut(dsn[:at], ":")
+	if !hasPassword {
+		return dsn
+	}
+	return user + ":" + redacted + dsn[at:]
+}
+
+func (l *Loaded) Redacted() map[string]interface{} {
+	cfg := l.Config
+	view := map[string]interface{}{}
+	for _, f := range fields(&cfg) {
+		var value interface{} = f.Value.Interface()
+		switch v := value.(type) {
+		case time.Duration:
+			value = v.String()
+		case fmt.Stringer:
+			value = v.String()
+		}
+		switch f.Secret {
+		case "dsn":
+			value = redactDSN(f.Value.String())
+		case "true":
+			if f.Value.String() != "" {
+				value = redacted
+			}
+		}
+
+		node := view
+		parts := strings.Split(f.Key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+	return view
+}
@@ -1,79 +1,168 @@
 package database
 
 import (
+	"coaching-backend/config"
 	"coaching-backend/models"
+	"coaching-backend/tracing"
+	"context"
+	"database/sql"
+	"fmt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
-	"log"
+	"log/slog"
 	"os"
-	"strconv"
+	"sync"
 	"time"
 )
 
 var DB *gorm.DB
 
-func Connect() {
-	dsn := os.Getenv("DB_DSN")
-	if dsn == "" {
-		dsn = "root:password@tcp(localhost:3306)/coaching_app?charset=utf8mb4&parseTime=True&loc=Local"
+var (
+	settings    config.Database
+	connections = &pool{}
+	reloadMu    sync.Mutex
+
+	retireGrace   = 30 * time.Second
+	retireTimeout = 5 * time.Minute
+
+	openSQL = func(dsn string) (*sql.DB, error) {
+		return sql.Open("mysql", dsn)
 	}
+)
 
+func Connect(cfg config.Database, env string) {
 	logLevel := logger.Error
-	if os.Getenv("APP_ENV") == "development" {
+	if env == "development" {
 		logLevel = logger.Info
 	}
 
-	config := &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
-	}
-
 	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), config)
+	DB, err = connect(cfg, logLevel, func(conn gorm.ConnPool) gorm.Dialector {
+		return mysql.New(mysql.Config{DSN: cfg.DSN, Conn: conn})
+	})
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 
-	sqlDB, err := DB.DB()
+	if err := models.AutoMigrate(DB); err != nil {
+		slog.Error("Failed to migrate database", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Database connected", "max_open_conns", cfg.MaxOpenConns, "max_idle_conns", cfg.MaxIdleConns)
+}
+
+func connect(cfg config.Database, logLevel logger.LogLevel, dialector func(gorm.ConnPool) gorm.Dialector) (*gorm.DB, error) {
+	sqlDB, err := openPool(cfg)
 	if err != nil {
-		log.Fatal("Failed to get underlying database connection:", err)
+		return nil, err
 	}
+	settings = cfg
+	connections.current.Store(sqlDB)
 
-	maxOpenConns := 25
-	if value := os.Getenv("DB_MAX_OPEN_CONNS"); value != "" {
-		if parsed, err := strconv.Atoi(value); err == nil {
-			maxOpenConns = parsed
-		}
+	db, err := gorm.Open(dialector(connections), &gorm.Config{
+		Logger: logger.Default.LogMode(logLevel),
+	})
+	if err != nil {
+		return nil, err
 	}
-	sqlDB.SetMaxOpenConns(maxOpenConns)
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("register tracing plugin: %w", err)
+	}
+	return db, nil
+}
 
-	maxIdleConns := 10
-	if value := os.Getenv("DB_MAX_IDLE_CONNS"); value != "" {
-		if parsed, err := strconv.Atoi(value); err == nil {
-			maxIdleConns = parsed
-		}
+func openPool(cfg config.Database) (*sql.DB, error) {
+	sqlDB, err := openSQL(cfg.DSN)
+	if err != nil {
+		return nil, err
 	}
-	sqlDB.SetMaxIdleConns(maxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return sqlDB, nil
+}
 
-	maxLifetime := 5 * time.Minute
-	if value := os.Getenv("DB_CONN_MAX_LIFETIME"); value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil {
-			maxLifetime = parsed
-		}
+func Reload(ctx context.Context, dsn string) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	cfg := settings
+	cfg.DSN = dsn
+	sqlDB, err := openPool(cfg)
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return err
 	}
-	sqlDB.SetConnMaxLifetime(maxLifetime)
 
-	maxIdleTime := 30 * time.Second
-	if value := os.Getenv("DB_CONN_MAX_IDLE_TIME"); value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil {
-			maxIdleTime = parsed
-		}
+	previous := connections.current.Swap(sqlDB)
+	settings = cfg
+	if previous != nil {
+		go retire(previous)
 	}
-	sqlDB.SetConnMaxIdleTime(maxIdleTime)
+	slog.Info("Database connection pool rebuilt with new credentials")
+	return nil
+}
 
-	if err := models.AutoMigrate(DB); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+func retire(previous *sql.DB) {
+	time.Sleep(retireGrace)
+	deadline := time.Now().Add(retireTimeout)
+	for previous.Stats().InUse > 0 && time.Now().Before(deadline) {
+		time.Sleep(retireGrace / 10)
+	}
+	if err := previous.Close(); err != nil {
+		slog.Warn("Failed to close previous database pool", "error", err)
 	}
+}
+
+func WatchDSNFile(ctx context.Context, path string, interval time.Duration) <-chan struct{} {
+	slog.Info("Watching database credentials", "file", path, "interval", interval.String())
 
-	log.Printf("Database connected successfully with %d max open connections", maxOpenConns)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			dsn, err := config.ReadSecretFile(path)
+			if err != nil {
+				slog.Warn("Failed to read database credentials", "file", path, "error", err)
+				continue
+			}
+			reloadMu.Lock()
+			unchanged := dsn == settings.DSN
+			reloadMu.Unlock()
+			if unchanged || dsn == "" {
+				continue
+			}
+			if err := Reload(ctx, dsn); err != nil {
+				slog.Error("Failed to connect with rotated database credentials, keeping the current pool", "file", path, "error", err)
+			}
+		}
+	}()
+	return done
+}
+
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/models"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReloadRebuildsPoolFromRotatedDSNFile(t *testing.T) {
+	openSQL = func(dsn string) (*sql.DB, error) {
+		return sql.Open(sqlite.DriverName, dsn)
+	}
+	retireGrace = 200 * time.Millisecond
+	t.Cleanup(func() {
+		openSQL = func(dsn string) (*sql.DB, error) {
+			return sql.Open("mysql", dsn)
+		}
+		retireGrace = 30 * time.Second
+	})
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.db")
+	second := filepath.Join(dir, "second.db")
+	dsnFile := filepath.Join(dir, "dsn")
+	if err := os.WriteFile(dsnFile, []byte(first+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default().Database
+	cfg.DSN = first
+	db, err := connect(cfg, logger.Silent, func(conn gorm.ConnPool) gorm.Dialector {
+		return sqlite.Dialector{Conn: conn}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Team{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Team{ID: "team-1", Name: "Platform"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	before, _ := db.DB()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := WatchDSNFile(ctx, dsnFile, 10*time.Millisecond)
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	if err := os.WriteFile(dsnFile, []byte(filepath.Join(dir, "missing", "x.db")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	var count int64
+	if err := db.Model(&models.Team{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("a failed reload must keep the current pool, got count=%d err=%v", count, err)
+	}
+
+	if err := os.WriteFile(dsnFile, []byte(second), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		reloadMu.Lock()
+		active := settings.DSN
+		reloadMu.Unlock()
+		if active == second {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("rotated DSN was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	after, _ := db.DB()
+	if after == before {
+		t.Fatal("the pool was not rebuilt")
+	}
+	if after.Stats().MaxOpenConnections != cfg.MaxOpenConns {
+		t.Errorf("pool settings were not carried over, max open %d", after.Stats().MaxOpenConnections)
+	}
+	if db.Migrator().HasTable(&models.Team{}) {
+		t.Error("queries should run against the new database")
+	}
+	if err := before.Ping(); err != nil {
+		t.Errorf("callers still holding the previous pool must be able to finish, got %v", err)
+	}
+	deadline = time.Now().Add(time.Second)
+	for before.Ping() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("the previous pool was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
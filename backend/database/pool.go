@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+type pool struct {
+	current atomic.Pointer[sql.DB]
+}
+
+func (p *pool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.current.Load().PrepareContext(ctx, query)
+}
+
+func (p *pool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.current.Load().ExecContext(ctx, query, args...)
+}
+
+func (p *pool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.current.Load().QueryContext(ctx, query, args...)
+}
+
+func (p *pool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.current.Load().QueryRowContext(ctx, query, args...)
+}
+
+func (p *pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.current.Load().BeginTx(ctx, opts)
+}
+
+func (p *pool) GetDBConn() (*sql.DB, error) {
+	return p.current.Load(), nil
+}
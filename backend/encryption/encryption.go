@@ -0,0 +1,179 @@
+package encryption
+
+import (
+	"coaching-backend/config"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	prefix       = "enc:v2:"
+	legacyPrefix = "enc:v1:"
+)
+
+var ErrNoKMS = errors.New("value is encrypted but no encryption keys are configured")
+
+type KMS interface {
+	ActiveKeyID() string
+	KeyIDs() []string
+	WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	DeriveKey(ctx context.Context, keyID, label string) ([]byte, error)
+}
+
+var (
+	keys        KMS
+	searchIndex bool
+)
+
+func Configure(kms KMS, withSearchIndex bool) {
+	keys = kms
+	searchIndex = kms != nil && withSearchIndex
+}
+
+func Enabled() bool {
+	return keys != nil
+}
+
+func SearchIndexEnabled() bool {
+	return searchIndex
+}
+
+func ActiveKeyID() string {
+	if keys == nil {
+		return ""
+	}
+	return keys.ActiveKeyID()
+}
+
+func IsEncrypted(stored string) bool {
+	return strings.HasPrefix(stored, prefix) || IsLegacy(stored)
+}
+
+func IsLegacy(stored string) bool {
+	return strings.HasPrefix(stored, legacyPrefix)
+}
+
+func envelope(stored string) string {
+	if IsLegacy(stored) {
+		return strings.TrimPrefix(stored, legacyPrefix)
+	}
+	return strings.TrimPrefix(stored, prefix)
+}
+
+func KeyID(stored string) string {
+	if !IsEncrypted(stored) {
+		return ""
+	}
+	keyID, _, _ := strings.Cut(envelope(stored), ":")
+	return keyID
+}
+
+func NeedsRotation(stored string) bool {
+	return keys != nil && (KeyID(stored) != keys.ActiveKeyID() || IsLegacy(stored))
+}
+
+func Encrypt(ctx context.Context, plaintext, aad string) (string, error) {
+	if keys == nil {
+		return plaintext, nil
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+	keyID := keys.ActiveKeyID()
+	wrapped, err := keys.WrapKey(ctx, keyID, dek)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key with %q: %w", keyID, err)
+	}
+	sealed, err := seal(dek, []byte(plaintext), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(wrapped) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func Decrypt(ctx context.Context, stored, aad string) (string, error) {
+	if !IsEncrypted(stored) {
+		return stored, nil
+	}
+	if keys == nil {
+		return "", ErrNoKMS
+	}
+
+	parts := strings.Split(envelope(stored), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed data key: %w", err)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed ciphertext: %w", err)
+	}
+
+	dek, err := keys.UnwrapKey(ctx, parts[0], wrapped)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key with %q: %w", parts[0], err)
+	}
+	plaintext, err := open(dek, sealed, []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("decrypt value with %q: %w", parts[0], err)
+	}
+	return string(plaintext), nil
+}
+
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func Setup(cfg config.Encryption) error {
+	if cfg.KeyFile == "" {
+		Configure(nil, false)
+		return nil
+	}
+	kms, err := LoadKeyFile(cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+	Configure(kms, cfg.SearchIndex)
+	return nil
+}
@@ -0,0 +1,118 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyLine(id string, b byte) string {
+	return id + " " + base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func keyring(t *testing.T, lines ...string) *LocalKMS {
+	kms, err := ParseKeyring([]byte("# test keys\n\n" + strings.Join(lines, "\n") + "\n"))
+	require.NoError(t, err)
+	return kms
+}
+
+func TestParseKeyring(t *testing.T) {
+	kms := keyring(t, keyLine("2025-01", 'a'), keyLine("2026-01", 'b'))
+	assert.Equal(t, "2026-01", kms.ActiveKeyID(), "the last key is active")
+	assert.Equal(t, []string{"2025-01", "2026-01"}, kms.KeyIDs())
+
+	for name, data := range map[string]string{
+		"empty":     "# nothing here\n",
+		"fields":    "2025-01\n",
+		"colon":     keyLine("2025:01", 'a'),
+		"base64":    "2025-01 not-base64!",
+		"short":     "2025-01 " + base64.StdEncoding.EncodeToString([]byte("short")),
+		"duplicate": keyLine("2025-01", 'a') + "\n" + keyLine("2025-01", 'b'),
+	} {
+		_, err := ParseKeyring([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	defer Configure(nil, false)
+	ctx := context.Background()
+
+	Configure(nil, false)
+	stored, err := Encrypt(ctx, "Great work", "feedbacks.content")
+	require.NoError(t, err)
+	assert.Equal(t, "Great work", stored, "without keys values are stored as-is")
+
+	Configure(keyring(t, keyLine("k1", 'a')), false)
+	stored, err = Encrypt(ctx, "Great work", "feedbacks.content")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, "enc:v2:k1:"), stored)
+	assert.NotContains(t, stored, "Great")
+	assert.Equal(t, "k1", KeyID(stored))
+
+	again, err := Encrypt(ctx, "Great work", "feedbacks.content")
+	require.NoError(t, err)
+	assert.NotEqual(t, stored, again, "every value gets its own data key and nonce")
+
+	plaintext, err := Decrypt(ctx, stored, "feedbacks.content")
+	require.NoError(t, err)
+	assert.Equal(t, "Great work", plaintext)
+
+	_, err = Decrypt(ctx, stored, "teams.name")
+	assert.Error(t, err, "ciphertext is bound to its column")
+
+	plaintext, err = Decrypt(ctx, "Legacy plaintext", "feedbacks.content")
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy plaintext", plaintext)
+
+	legacy := "enc:v1:" + strings.TrimPrefix(stored, "enc:v2:")
+	assert.True(t, IsLegacy(legacy))
+	assert.True(t, NeedsRotation(legacy), "values written before row binding are re-encrypted")
+	assert.False(t, NeedsRotation(stored))
+	plaintext, err = Decrypt(ctx, legacy, "feedbacks.content")
+	require.NoError(t, err)
+	assert.Equal(t, "Great work", plaintext)
+
+	Configure(keyring(t, keyLine("k1", 'a'), keyLine("k2", 'b')), false)
+	assert.True(t, NeedsRotation(stored))
+	assert.True(t, NeedsRotation("Legacy plaintext"))
+	plaintext, err = Decrypt(ctx, stored, "feedbacks.content")
+	require.NoError(t, err, "retired keys still decrypt")
+	assert.Equal(t, "Great work", plaintext)
+
+	Configure(keyring(t, keyLine("k2", 'b')), false)
+	_, err = Decrypt(ctx, stored, "feedbacks.content")
+	assert.ErrorContains(t, err, `unknown key id "k1"`)
+
+	Configure(nil, false)
+	_, err = Decrypt(ctx, stored, "feedbacks.content")
+	assert.ErrorIs(t, err, ErrNoKMS)
+}
+
+func TestSearchTokens(t *testing.T) {
+	defer Configure(nil, false)
+	ctx := context.Background()
+
+	assert.Equal(t, []string{"great", "work", "on", "the", "q3", "launch"}, Terms("Great work on the Q3 launch, great!"))
+
+	Configure(keyring(t, keyLine("k1", 'a')), false)
+	tokens, err := IndexTokens(ctx, "Great work")
+	require.NoError(t, err)
+	assert.Empty(t, tokens, "no tokens unless the search index is enabled")
+
+	Configure(keyring(t, keyLine("k1", 'a')), true)
+	tokens, err = IndexTokens(ctx, "Great work")
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.NotContains(t, tokens[0], "great")
+
+	Configure(keyring(t, keyLine("k1", 'a'), keyLine("k2", 'b')), true)
+	query, err := QueryTokens(ctx, "great")
+	require.NoError(t, err)
+	assert.Len(t, query, 2, "one token per key so rows indexed before a rotation still match")
+	assert.Contains(t, query, tokens[0])
+}
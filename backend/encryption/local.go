@@ -0,0 +1,114 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type LocalKMS struct {
+	keys   map[string][]byte
+	order  []string
+	active string
+}
+
+func LoadKeyFile(path string) (*LocalKMS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	kms, err := ParseKeyring(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return kms, nil
+}
+
+func ParseKeyring(data []byte) (*LocalKMS, error) {
+	kms := &LocalKMS{keys: map[string][]byte{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"<key-id> <base64 key>\"", line)
+		}
+		id := fields[0]
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("line %d: key id %q must not contain ':'", line, id)
+		}
+		if _, ok := kms.keys[id]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key id %q", line, id)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: key %q is not valid base64: %w", line, id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("line %d: key %q must be 32 bytes, got %d", line, id, len(key))
+		}
+
+		kms.keys[id] = key
+		kms.order = append(kms.order, id)
+		kms.active = id
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if kms.active == "" {
+		return nil, fmt.Errorf("no keys found")
+	}
+	return kms, nil
+}
+
+func (k *LocalKMS) ActiveKeyID() string {
+	return k.active
+}
+
+func (k *LocalKMS) KeyIDs() []string {
+	return append([]string(nil), k.order...)
+}
+
+func (k *LocalKMS) WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	kek, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(kek, dek, []byte(keyID))
+}
+
+func (k *LocalKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	kek, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return open(kek, wrapped, []byte(keyID))
+}
+
+func (k *LocalKMS) DeriveKey(ctx context.Context, keyID, label string) ([]byte, error) {
+	kek, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, kek)
+	mac.Write([]byte(label))
+	return mac.Sum(nil), nil
+}
+
+func (k *LocalKMS) key(keyID string) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", keyID)
+	}
+	return key, nil
+}
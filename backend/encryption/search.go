@@ -0,0 +1,70 @@
+package encryption
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+const (
+	indexLabel     = "feedback-search-index"
+	minTermLength  = 2
+	tokenHexLength = 32
+)
+
+func Terms(text string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, term := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(term)) < minTermLength || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func IndexTokens(ctx context.Context, text string) ([]string, error) {
+	if !searchIndex {
+		return nil, nil
+	}
+	indexKey, err := keys.DeriveKey(ctx, keys.ActiveKeyID(), indexLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := Terms(text)
+	tokens := make([]string, 0, len(terms))
+	for _, term := range terms {
+		tokens = append(tokens, token(indexKey, term))
+	}
+	return tokens, nil
+}
+
+func QueryTokens(ctx context.Context, term string) ([]string, error) {
+	if !searchIndex {
+		return nil, nil
+	}
+
+	var tokens []string
+	for _, keyID := range keys.KeyIDs() {
+		indexKey, err := keys.DeriveKey(ctx, keyID, indexLabel)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token(indexKey, term))
+	}
+	return tokens, nil
+}
+
+func token(indexKey []byte, term string) string {
+	mac := hmac.New(sha256.New, indexKey)
+	mac.Write([]byte(term))
+	return hex.EncodeToString(mac.Sum(nil))[:tokenHexLength]
+}
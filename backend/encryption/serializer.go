@@ -0,0 +1,68 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm/schema"
+	"reflect"
+)
+
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		stored = string(v)
+	case string:
+		stored = v
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	aad := ""
+	if IsEncrypted(stored) {
+		var err error
+		if aad, err = additionalData(ctx, field, dst, IsLegacy(stored)); err != nil {
+			return err
+		}
+	}
+	plaintext, err := Decrypt(ctx, stored, aad)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", field.Schema.Table, field.DBName, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string, got %T", field.Name, fieldValue)
+	}
+	if !Enabled() {
+		return plaintext, nil
+	}
+	aad, err := additionalData(ctx, field, dst, false)
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(ctx, plaintext, aad)
+}
+
+func additionalData(ctx context.Context, field *schema.Field, dst reflect.Value, legacy bool) (string, error) {
+	column := field.Schema.Table + "." + field.DBName
+	if legacy {
+		return column, nil
+	}
+	primary := field.Schema.PrioritizedPrimaryField
+	if primary == nil {
+		return "", fmt.Errorf("encrypted field %s needs a primary key", column)
+	}
+	id, zero := primary.ValueOf(ctx, dst)
+	if zero {
+		return "", fmt.Errorf("encrypted field %s needs %s to be set or selected first", column, primary.DBName)
+	}
+	return fmt.Sprintf("%s:%v", column, id), nil
+}
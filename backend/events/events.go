@@ -0,0 +1,100 @@
+package events
+
+import (
+	"coaching-backend/models"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+const (
+	FeedbackCreated  = "feedback.created"
+	FeedbackUpdated  = "feedback.updated"
+	FeedbackDeleted  = "feedback.deleted"
+	FeedbackPurged   = "feedback.purged"
+	MemberCreated    = "member.created"
+	MemberUpdated    = "member.updated"
+	MemberAssigned   = "member.assigned"
+	MemberUnassigned = "member.unassigned"
+	MemberDeleted    = "member.deleted"
+	MemberErased     = "member.erased"
+	TeamCreated      = "team.created"
+	TeamUpdated      = "team.updated"
+	TeamDeleted      = "team.deleted"
+	GoalCreated      = "goal.created"
+	GoalUpdated      = "goal.updated"
+	GoalDeleted      = "goal.deleted"
+)
+
+var Types = []string{
+	FeedbackCreated,
+	FeedbackUpdated,
+	FeedbackDeleted,
+	FeedbackPurged,
+	MemberCreated,
+	MemberUpdated,
+	MemberAssigned,
+	MemberUnassigned,
+	MemberDeleted,
+	MemberErased,
+	TeamCreated,
+	TeamUpdated,
+	TeamDeleted,
+	GoalCreated,
+	GoalUpdated,
+	GoalDeleted,
+}
+
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type Ref struct {
+	ID string `json:"id"`
+}
+
+type MembershipChange struct {
+	Member         models.TeamMember `json:"member"`
+	TeamID         *string           `json:"team_id"`
+	PreviousTeamID *string           `json:"previous_team_id"`
+}
+
+type Handler func(Event)
+
+var (
+	mu       sync.RWMutex
+	handlers []Handler
+)
+
+func Subscribe(handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers = append(handlers, handler)
+}
+
+func Emit(eventType string, data interface{}) {
+	event := Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+func IsValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"strings"
+	"sync"
+)
+
+type Entry struct {
+	Seq   uint64
+	Event Event
+}
+
+type Filter struct {
+	Entities []string
+	Types    []string
+}
+
+func (f Filter) Match(event Event) bool {
+	if len(f.Entities) > 0 && !contains(f.Entities, Entity(event.Type)) {
+		return false
+	}
+	if len(f.Types) > 0 && !contains(f.Types, event.Type) {
+		return false
+	}
+	return true
+}
+
+func Entity(eventType string) string {
+	if i := strings.Index(eventType, "."); i >= 0 {
+		return eventType[:i]
+	}
+	return eventType
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type Subscription struct {
+	C      chan Entry
+	filter Filter
+}
+
+type Hub struct {
+	mu      sync.Mutex
+	seq     uint64
+	size    int
+	buffer  []Entry
+	clients map[*Subscription]struct{}
+}
+
+func NewHub(size int) *Hub {
+	return &Hub{
+		size:    size,
+		buffer:  make([]Entry, 0, size),
+		clients: map[*Subscription]struct{}{},
+	}
+}
+
+var Stream = NewHub(1024)
+
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	entry := Entry{Seq: h.seq, Event: event}
+	if len(h.buffer) == h.size {
+		copy(h.buffer, h.buffer[1:])
+		h.buffer = h.buffer[:h.size-1]
+	}
+	h.buffer = append(h.buffer, entry)
+
+	for sub := range h.clients {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.C <- entry:
+		default:
+			delete(h.clients, sub)
+			close(sub.C)
+		}
+	}
+}
+
+func (h *Hub) LastSeq() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.seq
+}
+
+func (h *Hub) Subscribe(lastSeq uint64, filter Filter) (*Subscription, []Entry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	complete := true
+	var backlog []Entry
+	if lastSeq > h.seq {
+		complete = false
+		for _, entry := range h.buffer {
+			if filter.Match(entry.Event) {
+				backlog = append(backlog, entry)
+			}
+		}
+	} else if lastSeq > 0 && lastSeq < h.seq {
+		if len(h.buffer) == 0 || h.buffer[0].Seq > lastSeq+1 {
+			complete = false
+		}
+		for _, entry := range h.buffer {
+			if entry.Seq > lastSeq && filter.Match(entry.Event) {
+				backlog = append(backlog, entry)
+			}
+		}
+	}
+
+	sub := &Subscription{C: make(chan Entry, 64), filter: filter}
+	h.clients[sub] = struct{}{}
+	return sub, backlog, complete
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[sub]; ok {
+		delete(h.clients, sub)
+		close(sub.C)
+	}
+}
+
+func (h *Hub) Rewrite(rewrite func(Event) (Event, bool)) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rewritten := 0
+	for i := range h.buffer {
+		if event, ok := rewrite(h.buffer[i].Event); ok {
+			h.buffer[i].Event = event
+			rewritten++
+		}
+	}
+	return rewritten
+}
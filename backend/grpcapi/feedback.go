@@ -0,0 +1,58 @@
+package grpcapi
+
+import (
+	"coaching-backend/grpcapi/coachingpb"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"context"
+	"google.golang.org/grpc"
+)
+
+type feedbackServer struct {
+	coachingpb.UnimplementedFeedbackServiceServer
+}
+
+func (s *feedbackServer) ListFeedbacks(req *coachingpb.ListFeedbacksRequest, stream grpc.ServerStreamingServer[coachingpb.Feedback]) error {
+	feedbacks, err := services.ListFeedbacks(stream.Context(), services.FeedbackFilter{TargetType: req.GetTargetType(), TargetID: req.GetTargetId(), Query: req.GetQuery()})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for _, feedback := range feedbacks {
+		if err := stream.Send(toFeedback(feedback)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *feedbackServer) GetFeedback(ctx context.Context, req *coachingpb.GetFeedbackRequest) (*coachingpb.Feedback, error) {
+	feedback, err := services.GetFeedback(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toFeedback(feedback), nil
+}
+
+func (s *feedbackServer) CreateFeedback(ctx context.Context, req *coachingpb.CreateFeedbackRequest) (*coachingpb.Feedback, error) {
+	feedback := models.Feedback{Content: req.GetContent(), TargetType: req.GetTargetType(), TargetID: req.GetTargetId()}
+	if err := services.CreateFeedback(ctx, &feedback); err != nil {
+		return nil, toStatus(err)
+	}
+	return toFeedback(feedback), nil
+}
+
+func (s *feedbackServer) UpdateFeedback(ctx context.Context, req *coachingpb.UpdateFeedbackRequest) (*coachingpb.Feedback, error) {
+	feedback, err := services.UpdateFeedback(ctx, req.GetId(), models.Feedback{Content: req.GetContent(), TargetType: req.GetTargetType(), TargetID: req.GetTargetId()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toFeedback(feedback), nil
+}
+
+func (s *feedbackServer) DeleteFeedback(ctx context.Context, req *coachingpb.DeleteFeedbackRequest) (*coachingpb.DeleteFeedbackResponse, error) {
+	if err := services.DeleteFeedback(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &coachingpb.DeleteFeedbackResponse{}, nil
+}
@@ -0,0 +1,56 @@
+package grpcapi
+
+import (
+	"coaching-backend/grpcapi/coachingpb"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"context"
+)
+
+type membersServer struct {
+	coachingpb.UnimplementedMembersServiceServer
+}
+
+func (s *membersServer) ListMembers(ctx context.Context, req *coachingpb.ListMembersRequest) (*coachingpb.ListMembersResponse, error) {
+	members, err := services.ListMembers(ctx, services.MemberFilter{})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &coachingpb.ListMembersResponse{Members: make([]*coachingpb.TeamMember, 0, len(members))}
+	for _, member := range members {
+		resp.Members = append(resp.Members, toMember(member))
+	}
+	return resp, nil
+}
+
+func (s *membersServer) GetMember(ctx context.Context, req *coachingpb.GetMemberRequest) (*coachingpb.TeamMember, error) {
+	member, err := services.GetMember(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toMember(member), nil
+}
+
+func (s *membersServer) CreateMember(ctx context.Context, req *coachingpb.CreateMemberRequest) (*coachingpb.TeamMember, error) {
+	member := models.TeamMember{Name: req.GetName(), Email: req.GetEmail(), Picture: req.GetPicture()}
+	if err := services.CreateMember(ctx, &member); err != nil {
+		return nil, toStatus(err)
+	}
+	return toMember(member), nil
+}
+
+func (s *membersServer) UpdateMember(ctx context.Context, req *coachingpb.UpdateMemberRequest) (*coachingpb.TeamMember, error) {
+	member, err := services.UpdateMember(ctx, req.GetId(), models.TeamMember{Name: req.GetName(), Email: req.GetEmail(), Picture: req.GetPicture()}, "name", "email")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toMember(member), nil
+}
+
+func (s *membersServer) DeleteMember(ctx context.Context, req *coachingpb.DeleteMemberRequest) (*coachingpb.DeleteMemberResponse, error) {
+	if err := services.DeleteMember(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &coachingpb.DeleteMemberResponse{}, nil
+}
@@ -0,0 +1,116 @@
+package grpcapi
+
+import (
+	"coaching-backend/grpcapi/coachingpb"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(logUnary),
+		grpc.ChainStreamInterceptor(logStream),
+	}, opts...)...)
+	coachingpb.RegisterTeamsServiceServer(server, &teamsServer{})
+	coachingpb.RegisterMembersServiceServer(server, &membersServer{})
+	coachingpb.RegisterFeedbackServiceServer(server, &feedbackServer{})
+	return server
+}
+
+func logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, err, start)
+	return resp, err
+}
+
+func logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(ss.Context(), info.FullMethod, err, start)
+	return err
+}
+
+func logCall(ctx context.Context, method string, err error, start time.Time) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "gRPC call completed", "method", method, "code", code.String(), logging.Latency(start))
+}
+
+func toStatus(err *services.Error) error {
+	code := codes.Internal
+	switch err.Status {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+		if err.Title == "Conflict" {
+			code = codes.Aborted
+		}
+	}
+	return status.Error(code, err.Title+": "+err.Message)
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toMember(member models.TeamMember) *coachingpb.TeamMember {
+	return &coachingpb.TeamMember{
+		Id:        member.ID,
+		Name:      member.Name,
+		Picture:   member.Picture,
+		Email:     member.Email,
+		TeamId:    member.TeamID,
+		CreatedAt: timestamp(member.CreatedAt),
+		UpdatedAt: timestamp(member.UpdatedAt),
+	}
+}
+
+func toTeam(team models.Team) *coachingpb.Team {
+	members := make([]*coachingpb.TeamMember, 0, len(team.Members))
+	for _, member := range team.Members {
+		members = append(members, toMember(member))
+	}
+	return &coachingpb.Team{
+		Id:        team.ID,
+		Name:      team.Name,
+		Logo:      team.Logo,
+		Members:   members,
+		CreatedAt: timestamp(team.CreatedAt),
+		UpdatedAt: timestamp(team.UpdatedAt),
+	}
+}
+
+func toFeedback(feedback models.Feedback) *coachingpb.Feedback {
+	return &coachingpb.Feedback{
+		Id:         feedback.ID,
+		Content:    feedback.Content,
+		TargetType: feedback.TargetType,
+		TargetId:   feedback.TargetID,
+		TargetName: feedback.TargetName,
+		CreatedAt:  timestamp(feedback.CreatedAt),
+		UpdatedAt:  timestamp(feedback.UpdatedAt),
+	}
+}
@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"coaching-backend/grpcapi/coachingpb"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"context"
+)
+
+type teamsServer struct {
+	coachingpb.UnimplementedTeamsServiceServer
+}
+
+func (s *teamsServer) ListTeams(ctx context.Context, req *coachingpb.ListTeamsRequest) (*coachingpb.ListTeamsResponse, error) {
+	teams, err := services.ListTeams(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &coachingpb.ListTeamsResponse{Teams: make([]*coachingpb.Team, 0, len(teams))}
+	for _, team := range teams {
+		resp.Teams = append(resp.Teams, toTeam(team))
+	}
+	return resp, nil
+}
+
+func (s *teamsServer) GetTeam(ctx context.Context, req *coachingpb.GetTeamRequest) (*coachingpb.Team, error) {
+	team, err := services.GetTeam(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTeam(team), nil
+}
+
+func (s *teamsServer) CreateTeam(ctx context.Context, req *coachingpb.CreateTeamRequest) (*coachingpb.Team, error) {
+	team := models.Team{Name: req.GetName(), Logo: req.GetLogo()}
+	if err := services.CreateTeam(ctx, &team); err != nil {
+		return nil, toStatus(err)
+	}
+	return toTeam(team), nil
+}
+
+func (s *teamsServer) UpdateTeam(ctx context.Context, req *coachingpb.UpdateTeamRequest) (*coachingpb.Team, error) {
+	team, err := services.UpdateTeam(ctx, req.GetId(), models.Team{Name: req.GetName(), Logo: req.GetLogo()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTeam(team), nil
+}
+
+func (s *teamsServer) DeleteTeam(ctx context.Context, req *coachingpb.DeleteTeamRequest) (*coachingpb.DeleteTeamResponse, error) {
+	if err := services.DeleteTeam(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &coachingpb.DeleteTeamResponse{}, nil
+}
+
+func (s *teamsServer) AssignMember(ctx context.Context, req *coachingpb.AssignMemberRequest) (*coachingpb.TeamMember, error) {
+	member, err := services.AssignMember(ctx, req.GetMemberId(), req.GetTeamId(), req.ExpectedTeamId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toMember(member), nil
+}
+
+func (s *teamsServer) RemoveMember(ctx context.Context, req *coachingpb.RemoveMemberRequest) (*coachingpb.TeamMember, error) {
+	member, err := services.RemoveMember(ctx, req.GetMemberId(), req.ExpectedTeamId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toMember(member), nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"coaching-backend/images"
+	"coaching-backend/logging"
+	"coaching-backend/services"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+func generatedParams(c *gin.Context) (string, string, int, bool) {
+	file := c.Param("file")
+	format := strings.TrimPrefix(path.Ext(file), ".")
+	id := strings.TrimSuffix(file, path.Ext(file))
+
+	size := images.DefaultGeneratedSize
+	if value := c.Query("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "size must be an integer",
+			})
+			return "", "", 0, false
+		}
+		size = parsed
+	}
+	return id, format, size, true
+}
+
+func serveGenerated(c *gin.Context, image services.GeneratedImage, size int, cacheControl string) {
+	sum := sha256.Sum256([]byte(image.ContentType + "\x00" + strconv.Itoa(size) + "\x00" + image.Version))
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	c.Header("Cache-Control", cacheControl)
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, image.ContentType, image.Data)
+}
+
+func GeneratedMemberAvatar(c *gin.Context) {
+	id, format, size, ok := generatedParams(c)
+	if !ok {
+		return
+	}
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GeneratedMemberAvatar", "member_id", id)
+
+	image, err := services.MemberInitialsAvatar(c.Request.Context(), id, format, size)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+	serveGenerated(c, image, size, "public, max-age=3600")
+}
+
+func GeneratedTeamLogo(c *gin.Context) {
+	id, format, size, ok := generatedParams(c)
+	if !ok {
+		return
+	}
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GeneratedTeamLogo", "team_id", id)
+
+	image, err := services.TeamIdenticon(c.Request.Context(), id, format, size)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+	serveGenerated(c, image, size, "public, max-age=31536000, immutable")
+}
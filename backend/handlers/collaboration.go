@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"coaching-backend/events"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var AllowedOrigins []string
+
+func MatchOrigin(allowedOrigins []string, origin string) string {
+	wildcard := false
+	for _, allowed := range allowedOrigins {
+		if origin == allowed {
+			return origin
+		}
+		wildcard = wildcard || allowed == "*"
+	}
+	if wildcard {
+		return "*"
+	}
+	return ""
+}
+
+var (
+	closing     = make(chan struct{})
+	closingOnce sync.Once
+)
+
+func CloseStreams() {
+	closingOnce.Do(func() {
+		close(closing)
+	})
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || MatchOrigin(AllowedOrigins, origin) != ""
+	},
+}
+
+const (
+	wsWriteTimeout = 10 * time.Second
+	wsPongTimeout  = 60 * time.Second
+	wsPingInterval = 30 * time.Second
+)
+
+type wsCommand struct {
+	Type           string  `json:"type"`
+	RequestID      string  `json:"request_id,omitempty"`
+	TeamID         string  `json:"team_id,omitempty"`
+	MemberID       string  `json:"member_id,omitempty"`
+	ExpectedTeamID *string `json:"expected_team_id,omitempty"`
+}
+
+type wsMessage struct {
+	Type           string             `json:"type"`
+	RequestID      string             `json:"request_id,omitempty"`
+	Event          string             `json:"event,omitempty"`
+	TeamID         *string            `json:"team_id,omitempty"`
+	PreviousTeamID *string            `json:"previous_team_id,omitempty"`
+	Member         *models.TeamMember `json:"member,omitempty"`
+	Team           *models.Team       `json:"team,omitempty"`
+	Status         int                `json:"status,omitempty"`
+	Error          string             `json:"error,omitempty"`
+	Message        string             `json:"message,omitempty"`
+}
+
+type wsClient struct {
+	send chan wsMessage
+}
+
+type teamRooms struct {
+	mu    sync.Mutex
+	rooms map[string]map[*wsClient]struct{}
+}
+
+var rooms = &teamRooms{rooms: map[string]map[*wsClient]struct{}{}}
+
+func (r *teamRooms) join(teamID string, client *wsClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rooms[teamID] == nil {
+		r.rooms[teamID] = map[*wsClient]struct{}{}
+	}
+	r.rooms[teamID][client] = struct{}{}
+}
+
+func (r *teamRooms) leave(teamID string, client *wsClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.rooms[teamID], client)
+	if len(r.rooms[teamID]) == 0 {
+		delete(r.rooms, teamID)
+	}
+}
+
+func (r *teamRooms) leaveAll(client *wsClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for teamID, clients := range r.rooms {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(r.rooms, teamID)
+		}
+	}
+}
+
+func (r *teamRooms) broadcast(msg wsMessage, teamIDs ...*string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recipients := map[*wsClient]struct{}{}
+	for _, teamID := range teamIDs {
+		if teamID == nil {
+			continue
+		}
+		for client := range r.rooms[*teamID] {
+			recipients[client] = struct{}{}
+		}
+	}
+
+	for client := range recipients {
+		select {
+		case client.send <- msg:
+		default:
+			slog.Warn("Dropping collaboration message for slow client", "type", msg.Type)
+		}
+	}
+}
+
+func BroadcastMembershipChange(event events.Event) {
+	switch event.Type {
+	case events.MemberAssigned, events.MemberUnassigned:
+		change, ok := event.Data.(events.MembershipChange)
+		if !ok {
+			return
+		}
+		member := change.Member
+		rooms.broadcast(wsMessage{
+			Type:           "membership",
+			Event:          event.Type,
+			TeamID:         change.TeamID,
+			PreviousTeamID: change.PreviousTeamID,
+			Member:         &member,
+		}, change.TeamID, change.PreviousTeamID)
+	case events.TeamDeleted:
+		ref, ok := event.Data.(events.Ref)
+		if !ok {
+			return
+		}
+		rooms.broadcast(wsMessage{
+			Type:   "team_deleted",
+			Event:  event.Type,
+			TeamID: &ref.ID,
+		}, &ref.ID)
+	}
+}
+
+func TeamCollaboration(c *gin.Context) {
+	logger := logging.FromContext(c.Request.Context()).With("handler", "TeamCollaboration")
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("Upgrade failed", "error", err)
+		return
+	}
+	logger.Info("Client connected", "client_ip", c.ClientIP())
+
+	client := &wsClient{send: make(chan wsMessage, 64)}
+	done := make(chan struct{})
+	go writeMessages(conn, client, done)
+	go func() {
+		select {
+		case <-closing:
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(wsWriteTimeout))
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	defer func() {
+		rooms.leaveAll(client)
+		close(client.send)
+		<-done
+		conn.Close()
+		logger.Info("Client disconnected", "client_ip", c.ClientIP())
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	reply := func(msg wsMessage) {
+		select {
+		case client.send <- msg:
+		case <-done:
+		}
+	}
+
+	if teamID := c.Query("team_id"); teamID != "" {
+		reply(handleCommand(c.Request.Context(), client, wsCommand{Type: "subscribe", TeamID: teamID}))
+	}
+
+	for {
+		var cmd wsCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				reply(wsMessage{
+					Type:    "error",
+					Status:  http.StatusBadRequest,
+					Error:   "Invalid request format",
+					Message: "Commands must be JSON objects",
+				})
+				continue
+			}
+			return
+		}
+		reply(handleCommand(c.Request.Context(), client, cmd))
+	}
+}
+
+func writeMessages(conn *websocket.Conn, client *wsClient, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-client.send:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				conn.Close()
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func commandError(cmd wsCommand, err *services.Error) wsMessage {
+	return wsMessage{
+		Type:      "error",
+		RequestID: cmd.RequestID,
+		Status:    err.Status,
+		Error:     err.Title,
+		Message:   err.Message,
+	}
+}
+
+func handleCommand(ctx context.Context, client *wsClient, cmd wsCommand) wsMessage {
+	logger := logging.FromContext(ctx).With("handler", "TeamCollaboration", "command", cmd.Type, "request_id", cmd.RequestID, "member_id", cmd.MemberID, "team_id", cmd.TeamID)
+	switch cmd.Type {
+	case "subscribe":
+		team, err := services.GetTeam(ctx, cmd.TeamID)
+		if err != nil {
+			return commandError(cmd, err)
+		}
+		rooms.join(team.ID, client)
+		return wsMessage{Type: "ack", RequestID: cmd.RequestID, TeamID: &team.ID, Team: &team}
+
+	case "unsubscribe":
+		rooms.leave(cmd.TeamID, client)
+		return wsMessage{Type: "ack", RequestID: cmd.RequestID, TeamID: &cmd.TeamID}
+
+	case "assign":
+		if cmd.MemberID == "" || cmd.TeamID == "" {
+			return commandError(cmd, &services.Error{
+				Status:  http.StatusBadRequest,
+				Title:   "Invalid request format",
+				Message: "Member ID and Team ID are required",
+			})
+		}
+		member, err := services.AssignMember(ctx, cmd.MemberID, cmd.TeamID, cmd.ExpectedTeamID)
+		if err != nil {
+			logError(logger, err)
+			return commandError(cmd, err)
+		}
+		logger.Info("Assigned member to team")
+		return wsMessage{Type: "ack", RequestID: cmd.RequestID, TeamID: member.TeamID, Member: &member}
+
+	case "remove":
+		if cmd.MemberID == "" {
+			return commandError(cmd, &services.Error{
+				Status:  http.StatusBadRequest,
+				Title:   "Invalid request",
+				Message: "Member ID is required",
+			})
+		}
+		member, err := services.RemoveMember(ctx, cmd.MemberID, cmd.ExpectedTeamID)
+		if err != nil {
+			logError(logger, err)
+			return commandError(cmd, err)
+		}
+		logger.Info("Removed member from team")
+		return wsMessage{Type: "ack", RequestID: cmd.RequestID, Member: &member}
+	}
+
+	return commandError(cmd, &services.Error{
+		Status:  http.StatusBadRequest,
+		Title:   "Invalid request",
+		Message: "Unknown command type '" + cmd.Type + "'",
+	})
+}
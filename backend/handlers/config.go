@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var Config *config.Loaded
+
+type ConfigView struct {
+	File    string                 `json:"file"`
+	Config  map[string]interface{} `json:"config" binding:"required"`
+	Sources map[string]string      `json:"sources" binding:"required"`
+}
+
+func GetConfig(c *gin.Context) {
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetConfig")
+
+	loaded := Config
+	if loaded == nil {
+		loaded = &config.Loaded{Config: config.Default(), Sources: map[string]string{}}
+	}
+
+	logger.Info("Configuration viewed", "client_ip", c.ClientIP())
+	c.JSON(http.StatusOK, ConfigView{
+		File:    loaded.Path,
+		Config:  loaded.Redacted(),
+		Sources: loaded.Sources,
+	})
+}
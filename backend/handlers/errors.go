@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"coaching-backend/services"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+func logError(logger *slog.Logger, err *services.Error) {
+	cause := error(err)
+	if err.Err != nil {
+		cause = err.Err
+	}
+	if err.Status >= http.StatusInternalServerError {
+		logger.Error(err.Title, "status", err.Status, "error", cause)
+		return
+	}
+	logger.Warn(err.Title, "status", err.Status, "error", cause)
+}
+
+func respondError(c *gin.Context, err *services.Error) {
+	c.JSON(err.Status, gin.H{
+		"error":   err.Title,
+		"message": err.Message,
+	})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type FeedbackGoalRequest struct {
+	GoalID *string `json:"goal_id"`
+}
+
+func CreateGoal(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "CreateGoal")
+	logger.Debug("Request started")
+
+	var goal models.Goal
+	if err := c.ShouldBindJSON(&goal); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Please check your input data",
+		})
+		return
+	}
+
+	if err := services.CreateGoal(c.Request.Context(), &goal); err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Created goal", "goal_id", goal.ID, logging.Latency(start))
+	c.JSON(http.StatusCreated, goal)
+}
+
+func GetGoals(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetGoals")
+	logger.Debug("Request started")
+
+	goals, err := services.ListGoals(c.Request.Context(), services.GoalFilter{OwnerType: c.Query("owner_type"), OwnerID: c.Query("owner_id"), Status: c.Query("status")})
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Debug("Fetched goals", "count", len(goals), logging.Latency(start))
+	c.JSON(http.StatusOK, goals)
+}
+
+func GetGoal(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetGoal", "goal_id", id)
+	logger.Debug("Request started")
+
+	goal, err := services.GetGoal(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Debug("Fetched goal", logging.Latency(start))
+	c.JSON(http.StatusOK, goal)
+}
+
+func UpdateGoal(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UpdateGoal", "goal_id", id)
+	logger.Debug("Request started")
+
+	var updateData models.Goal
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Please check your input data",
+		})
+		return
+	}
+
+	goal, err := services.UpdateGoal(c.Request.Context(), id, updateData)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Updated goal", "status", goal.Status, "progress", goal.Progress, logging.Latency(start))
+	c.JSON(http.StatusOK, goal)
+}
+
+func DeleteGoal(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "DeleteGoal", "goal_id", id)
+	logger.Debug("Request started")
+
+	if err := services.DeleteGoal(c.Request.Context(), id); err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Deleted goal", logging.Latency(start))
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+func GetGoalFeedbacks(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetGoalFeedbacks", "goal_id", id)
+	logger.Debug("Request started")
+
+	feedbacks, err := services.ListGoalFeedbacks(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Debug("Fetched goal feedbacks", "count", len(feedbacks), logging.Latency(start))
+	c.JSON(http.StatusOK, feedbacks)
+}
+
+func SetFeedbackGoal(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "SetFeedbackGoal", "feedback_id", id)
+	logger.Debug("Request started")
+
+	var request FeedbackGoalRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "goal_id must be a goal ID or null",
+		})
+		return
+	}
+	if request.GoalID != nil && *request.GoalID == "" {
+		request.GoalID = nil
+	}
+
+	feedback, err := services.SetFeedbackGoal(c.Request.Context(), id, request.GoalID)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Updated feedback goal", "linked", feedback.GoalID != nil, logging.Latency(start))
+	c.JSON(http.StatusOK, feedback)
+}
@@ -0,0 +1,487 @@
+package handlers
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"coaching-backend/services"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"net/http"
+	"time"
+)
+
+const (
+	graphqlDefaultLimit  = 50
+	graphqlMaxLimit      = 100
+	graphqlFeedbackLimit = 5
+)
+
+func pageArgs(defaultLimit int) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultLimit},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+}
+
+func withArgs(sets ...graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{}
+	for _, set := range sets {
+		for name, arg := range set {
+			args[name] = arg
+		}
+	}
+	return args
+}
+
+func pagination(p graphql.ResolveParams) (int, int) {
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+	if limit <= 0 {
+		limit = graphqlDefaultLimit
+	}
+	if limit > graphqlMaxLimit {
+		limit = graphqlMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func graphqlDatabaseError(ctx context.Context, message string, err error) error {
+	wrapped := &services.Error{Status: http.StatusInternalServerError, Title: "Database error", Message: message, Err: err}
+	logError(logging.FromContext(ctx).With("handler", "GraphQL"), wrapped)
+	return wrapped
+}
+
+func stringArg(p graphql.ResolveParams, name string) string {
+	value, _ := p.Args[name].(string)
+	return value
+}
+
+func optionalStringArg(p graphql.ResolveParams, name string) *string {
+	if value, ok := p.Args[name].(string); ok {
+		return &value
+	}
+	return nil
+}
+
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+var graphqlSchema = buildGraphqlSchema()
+
+func buildGraphqlSchema() graphql.Schema {
+	feedbackType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Feedback",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"content":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"targetType": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"targetId":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"targetName": &graphql.Field{Type: graphql.String},
+			"createdAt":  &graphql.Field{Type: graphql.DateTime},
+			"updatedAt":  &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	var teamType *graphql.Object
+
+	memberType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TeamMember",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"picture":   &graphql.Field{Type: graphql.String},
+				"email":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"teamId":    &graphql.Field{Type: graphql.ID},
+				"createdAt": &graphql.Field{Type: graphql.DateTime},
+				"updatedAt": &graphql.Field{Type: graphql.DateTime},
+				"team": &graphql.Field{
+					Type: teamType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						team, err := loaderFrom(p.Context).teamOf(p.Source.(models.TeamMember))
+						if err != nil || team == nil {
+							return nil, err
+						}
+						return *team, nil
+					},
+				},
+				"feedback": &graphql.Field{
+					Type: graphql.NewList(feedbackType),
+					Args: pageArgs(graphqlFeedbackLimit),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						limit, offset := pagination(p)
+						member := p.Source.(models.TeamMember)
+						return loaderFrom(p.Context).feedbackFor("member", member.ID, limit, offset)
+					},
+				},
+			}
+		}),
+	})
+
+	teamType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Team",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"logo":      &graphql.Field{Type: graphql.String},
+			"createdAt": &graphql.Field{Type: graphql.DateTime},
+			"updatedAt": &graphql.Field{Type: graphql.DateTime},
+			"members": &graphql.Field{
+				Type: graphql.NewList(memberType),
+				Args: pageArgs(graphqlDefaultLimit),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset := pagination(p)
+					team := p.Source.(models.Team)
+					members, err := loaderFrom(p.Context).membersOf(team.ID)
+					if err != nil {
+						return nil, err
+					}
+					return paginate(members, limit, offset), nil
+				},
+			},
+			"feedback": &graphql.Field{
+				Type: graphql.NewList(feedbackType),
+				Args: pageArgs(graphqlFeedbackLimit),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset := pagination(p)
+					team := p.Source.(models.Team)
+					return loaderFrom(p.Context).feedbackFor("team", team.ID, limit, offset)
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"teams": &graphql.Field{
+				Type: graphql.NewList(teamType),
+				Args: withArgs(pageArgs(graphqlDefaultLimit), graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.String},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset := pagination(p)
+					query := database.DB.WithContext(p.Context).Order("name").Limit(limit).Offset(offset)
+					if name := stringArg(p, "name"); name != "" {
+						query = query.Where("name LIKE ?", "%"+name+"%")
+					}
+					var teams []models.Team
+					if err := query.Find(&teams).Error; err != nil {
+						return nil, graphqlDatabaseError(p.Context, "Failed to fetch teams", err)
+					}
+					loaderFrom(p.Context).seeTeams(teams)
+					return teams, nil
+				},
+			},
+			"team": &graphql.Field{
+				Type: teamType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var teams []models.Team
+					if err := database.DB.WithContext(p.Context).Where("id = ?", stringArg(p, "id")).Limit(1).Find(&teams).Error; err != nil {
+						return nil, graphqlDatabaseError(p.Context, "Failed to fetch team", err)
+					}
+					if len(teams) == 0 {
+						return nil, nil
+					}
+					loaderFrom(p.Context).seeTeams(teams)
+					return teams[0], nil
+				},
+			},
+			"members": &graphql.Field{
+				Type: graphql.NewList(memberType),
+				Args: withArgs(pageArgs(graphqlDefaultLimit), graphql.FieldConfigArgument{
+					"teamId":     &graphql.ArgumentConfig{Type: graphql.ID},
+					"unassigned": &graphql.ArgumentConfig{Type: graphql.Boolean},
+					"name":       &graphql.ArgumentConfig{Type: graphql.String},
+					"email":      &graphql.ArgumentConfig{Type: graphql.String},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset := pagination(p)
+					query := database.DB.WithContext(p.Context).Order("name").Limit(limit).Offset(offset)
+					if teamID := stringArg(p, "teamId"); teamID != "" {
+						query = query.Where("team_id = ?", teamID)
+					}
+					if unassigned, ok := p.Args["unassigned"].(bool); ok {
+						if unassigned {
+							query = query.Where("team_id IS NULL")
+						} else {
+							query = query.Where("team_id IS NOT NULL")
+						}
+					}
+					if name := stringArg(p, "name"); name != "" {
+						query = query.Where("name LIKE ?", "%"+name+"%")
+					}
+					if email := stringArg(p, "email"); email != "" {
+						query = query.Where("email = ?", email)
+					}
+					var members []models.TeamMember
+					if err := query.Find(&members).Error; err != nil {
+						return nil, graphqlDatabaseError(p.Context, "Failed to fetch team members", err)
+					}
+					loaderFrom(p.Context).seeMembers(members)
+					return members, nil
+				},
+			},
+			"member": &graphql.Field{
+				Type: memberType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var members []models.TeamMember
+					if err := database.DB.WithContext(p.Context).Where("id = ?", stringArg(p, "id")).Limit(1).Find(&members).Error; err != nil {
+						return nil, graphqlDatabaseError(p.Context, "Failed to fetch team member", err)
+					}
+					if len(members) == 0 {
+						return nil, nil
+					}
+					loaderFrom(p.Context).seeMembers(members)
+					return members[0], nil
+				},
+			},
+			"feedbacks": &graphql.Field{
+				Type: graphql.NewList(feedbackType),
+				Args: withArgs(pageArgs(graphqlDefaultLimit), graphql.FieldConfigArgument{
+					"targetType": &graphql.ArgumentConfig{Type: graphql.String},
+					"targetId":   &graphql.ArgumentConfig{Type: graphql.ID},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset := pagination(p)
+					query := database.DB.WithContext(p.Context).Order("created_at DESC").Limit(limit).Offset(offset)
+					if targetType := stringArg(p, "targetType"); targetType != "" {
+						query = query.Where("target_type = ?", targetType)
+					}
+					if targetID := stringArg(p, "targetId"); targetID != "" {
+						query = query.Where("target_id = ?", targetID)
+					}
+					var feedbacks []models.Feedback
+					if err := query.Find(&feedbacks).Error; err != nil {
+						return nil, graphqlDatabaseError(p.Context, "Failed to fetch feedback", err)
+					}
+					return feedbacks, nil
+				},
+			},
+			"feedback": &graphql.Field{
+				Type: feedbackType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var feedbacks []models.Feedback
+					if err := database.DB.WithContext(p.Context).Where("id = ?", stringArg(p, "id")).Limit(1).Find(&feedbacks).Error; err != nil {
+						return nil, graphqlDatabaseError(p.Context, "Failed to fetch feedback", err)
+					}
+					if len(feedbacks) == 0 {
+						return nil, nil
+					}
+					return feedbacks[0], nil
+				},
+			},
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+	teamArgs := graphql.FieldConfigArgument{
+		"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"logo": &graphql.ArgumentConfig{Type: graphql.String},
+	}
+	memberArgs := graphql.FieldConfigArgument{
+		"name":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"email":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"picture": &graphql.ArgumentConfig{Type: graphql.String},
+	}
+	feedbackArgs := graphql.FieldConfigArgument{
+		"content":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"targetType": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"targetId":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTeam": &graphql.Field{
+				Type: teamType,
+				Args: teamArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					team := models.Team{Name: stringArg(p, "name"), Logo: stringArg(p, "logo")}
+					if err := services.CreateTeam(p.Context, &team); err != nil {
+						return nil, err
+					}
+					return team, nil
+				},
+			},
+			"updateTeam": &graphql.Field{
+				Type: teamType,
+				Args: withArgs(teamArgs, idArg),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					team, err := services.UpdateTeam(p.Context, stringArg(p, "id"), models.Team{Name: stringArg(p, "name"), Logo: stringArg(p, "logo")})
+					if err != nil {
+						return nil, err
+					}
+					return team, nil
+				},
+			},
+			"deleteTeam": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := services.DeleteTeam(p.Context, stringArg(p, "id")); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+			"createMember": &graphql.Field{
+				Type: memberType,
+				Args: memberArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					member := models.TeamMember{Name: stringArg(p, "name"), Email: stringArg(p, "email"), Picture: stringArg(p, "picture")}
+					if err := services.CreateMember(p.Context, &member); err != nil {
+						return nil, err
+					}
+					return member, nil
+				},
+			},
+			"updateMember": &graphql.Field{
+				Type: memberType,
+				Args: withArgs(memberArgs, idArg),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					member, err := services.UpdateMember(p.Context, stringArg(p, "id"), models.TeamMember{Name: stringArg(p, "name"), Email: stringArg(p, "email"), Picture: stringArg(p, "picture")}, "name", "email")
+					if err != nil {
+						return nil, err
+					}
+					return member, nil
+				},
+			},
+			"deleteMember": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := services.DeleteMember(p.Context, stringArg(p, "id")); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+			"assignMember": &graphql.Field{
+				Type: memberType,
+				Args: graphql.FieldConfigArgument{
+					"memberId":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"teamId":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"expectedTeamId": &graphql.ArgumentConfig{Type: graphql.ID},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					member, err := services.AssignMember(p.Context, stringArg(p, "memberId"), stringArg(p, "teamId"), optionalStringArg(p, "expectedTeamId"))
+					if err != nil {
+						return nil, err
+					}
+					return member, nil
+				},
+			},
+			"removeMember": &graphql.Field{
+				Type: memberType,
+				Args: graphql.FieldConfigArgument{
+					"memberId":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"expectedTeamId": &graphql.ArgumentConfig{Type: graphql.ID},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					member, err := services.RemoveMember(p.Context, stringArg(p, "memberId"), optionalStringArg(p, "expectedTeamId"))
+					if err != nil {
+						return nil, err
+					}
+					return member, nil
+				},
+			},
+			"createFeedback": &graphql.Field{
+				Type: feedbackType,
+				Args: feedbackArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					feedback := models.Feedback{Content: stringArg(p, "content"), TargetType: stringArg(p, "targetType"), TargetID: stringArg(p, "targetId")}
+					if err := services.CreateFeedback(p.Context, &feedback); err != nil {
+						return nil, err
+					}
+					return feedback, nil
+				},
+			},
+			"updateFeedback": &graphql.Field{
+				Type: feedbackType,
+				Args: withArgs(feedbackArgs, idArg),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					feedback, err := services.UpdateFeedback(p.Context, stringArg(p, "id"), models.Feedback{Content: stringArg(p, "content"), TargetType: stringArg(p, "targetType"), TargetID: stringArg(p, "targetId")})
+					if err != nil {
+						return nil, err
+					}
+					return feedback, nil
+				},
+			},
+			"deleteFeedback": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := services.DeleteFeedback(p.Context, stringArg(p, "id")); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+	if err != nil {
+		panic("failed to build GraphQL schema: " + err.Error())
+	}
+	return schema
+}
+
+type graphqlRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+func GraphQL(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GraphQL")
+	logger.Debug("Request started")
+
+	var req graphqlRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "A JSON body with a query is required",
+		})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), graphqlLoaderKey{}, newGraphqlLoader(c.Request.Context()))
+	result := graphql.Do(graphql.Params{
+		Schema:         graphqlSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+
+	logger.Info("Executed operation", "operation", req.OperationName, "errors", len(result.Errors), logging.Latency(start))
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"sync"
+)
+
+type graphqlLoaderKey struct{}
+
+type graphqlLoader struct {
+	ctx           context.Context
+	mu            sync.Mutex
+	teams         map[string]models.Team
+	members       map[string]models.TeamMember
+	membersByTeam map[string][]models.TeamMember
+	feedback      map[string]map[string][]models.Feedback
+}
+
+func newGraphqlLoader(ctx context.Context) *graphqlLoader {
+	return &graphqlLoader{
+		ctx:           ctx,
+		teams:         map[string]models.Team{},
+		members:       map[string]models.TeamMember{},
+		membersByTeam: map[string][]models.TeamMember{},
+		feedback:      map[string]map[string][]models.Feedback{},
+	}
+}
+
+func loaderFrom(ctx context.Context) *graphqlLoader {
+	if loader, ok := ctx.Value(graphqlLoaderKey{}).(*graphqlLoader); ok {
+		return loader
+	}
+	return newGraphqlLoader(ctx)
+}
+
+func (l *graphqlLoader) seeTeams(teams []models.Team) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, team := range teams {
+		l.teams[team.ID] = team
+	}
+}
+
+func (l *graphqlLoader) seeMembers(members []models.TeamMember) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, member := range members {
+		l.members[member.ID] = member
+	}
+}
+
+func (l *graphqlLoader) membersOf(teamID string) ([]models.TeamMember, error) {
+	l.mu.Lock()
+	if members, ok := l.membersByTeam[teamID]; ok {
+		l.mu.Unlock()
+		return members, nil
+	}
+
+	ids := []string{teamID}
+	for id := range l.teams {
+		if _, loaded := l.membersByTeam[id]; !loaded && id != teamID {
+			ids = append(ids, id)
+		}
+	}
+	l.mu.Unlock()
+
+	var members []models.TeamMember
+	if err := database.DB.WithContext(l.ctx).Where("team_id IN ?", ids).Order("name").Find(&members).Error; err != nil {
+		return nil, graphqlDatabaseError(l.ctx, "Failed to fetch team members", err)
+	}
+
+	l.mu.Lock()
+	for _, id := range ids {
+		l.membersByTeam[id] = []models.TeamMember{}
+	}
+	for _, member := range members {
+		l.membersByTeam[*member.TeamID] = append(l.membersByTeam[*member.TeamID], member)
+	}
+	result := l.membersByTeam[teamID]
+	l.mu.Unlock()
+
+	l.seeMembers(members)
+	return result, nil
+}
+
+func (l *graphqlLoader) teamOf(member models.TeamMember) (*models.Team, error) {
+	if member.TeamID == nil {
+		return nil, nil
+	}
+	teamID := *member.TeamID
+
+	l.mu.Lock()
+	if team, ok := l.teams[teamID]; ok {
+		l.mu.Unlock()
+		return &team, nil
+	}
+
+	ids := []string{teamID}
+	seen := map[string]bool{teamID: true}
+	for _, m := range l.members {
+		if m.TeamID == nil || seen[*m.TeamID] {
+			continue
+		}
+		if _, loaded := l.teams[*m.TeamID]; !loaded {
+			ids = append(ids, *m.TeamID)
+			seen[*m.TeamID] = true
+		}
+	}
+	l.mu.Unlock()
+
+	var teams []models.Team
+	if err := database.DB.WithContext(l.ctx).Where("id IN ?", ids).Find(&teams).Error; err != nil {
+		return nil, graphqlDatabaseError(l.ctx, "Failed to fetch teams", err)
+	}
+	l.seeTeams(teams)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if team, ok := l.teams[teamID]; ok {
+		return &team, nil
+	}
+	return nil, nil
+}
+
+func (l *graphqlLoader) feedbackFor(targetType, targetID string, limit, offset int) ([]models.Feedback, error) {
+	key := fmt.Sprintf("%s|%d|%d", targetType, limit, offset)
+
+	l.mu.Lock()
+	batch, ok := l.feedback[key]
+	if !ok {
+		batch = map[string][]models.Feedback{}
+		l.feedback[key] = batch
+	}
+	if items, loaded := batch[targetID]; loaded {
+		l.mu.Unlock()
+		return items, nil
+	}
+
+	ids := []string{targetID}
+	add := func(id string) {
+		if _, loaded := batch[id]; !loaded && id != targetID {
+			ids = append(ids, id)
+		}
+	}
+	if targetType == "team" {
+		for id := range l.teams {
+			add(id)
+		}
+	} else {
+		for id := range l.members {
+			add(id)
+		}
+	}
+	l.mu.Unlock()
+
+	ranked := database.DB.WithContext(l.ctx).Model(&models.Feedback{}).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY target_id ORDER BY created_at DESC) AS row_num").
+		Where("target_type = ? AND target_id IN ?", targetType, ids)
+
+	var feedbacks []models.Feedback
+	if err := database.DB.WithContext(l.ctx).Table("(?) AS ranked", ranked).
+		Where("row_num > ? AND row_num <= ?", offset, offset+limit).
+		Order("created_at DESC").
+		Find(&feedbacks).Error; err != nil {
+		return nil, graphqlDatabaseError(l.ctx, "Failed to fetch feedback", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		batch[id] = []models.Feedback{}
+	}
+	for _, feedback := range feedbacks {
+		batch[feedback.TargetID] = append(batch[feedback.TargetID], feedback)
+	}
+	return batch[targetID], nil
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"coaching-backend/blob"
+	"coaching-backend/logging"
+	"coaching-backend/services"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var MaxUploadBytes int64 = 5 << 20
+
+func readUpload(c *gin.Context) ([]byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxUploadBytes+64<<10)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   "Upload too large",
+				"message": "Images must be at most " + strconv.FormatInt(MaxUploadBytes, 10) + " bytes",
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Upload the image as multipart/form-data in the file field",
+		})
+		return nil, false
+	}
+	if file.Size > MaxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Upload too large",
+			"message": "Images must be at most " + strconv.FormatInt(MaxUploadBytes, 10) + " bytes",
+		})
+		return nil, false
+	}
+
+	opened, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "The uploaded file could not be read",
+		})
+		return nil, false
+	}
+	defer opened.Close()
+	data, err := io.ReadAll(opened)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "The uploaded file could not be read",
+		})
+		return nil, false
+	}
+	return data, true
+}
+
+func UploadMemberAvatar(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UploadMemberAvatar", "member_id", id)
+	logger.Debug("Request started")
+
+	data, ok := readUpload(c)
+	if !ok {
+		logger.Warn("Invalid upload")
+		return
+	}
+
+	member, err := services.SetMemberAvatar(c.Request.Context(), id, data)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Uploaded member avatar", "bytes", len(data), logging.Latency(start))
+	c.JSON(http.StatusOK, member)
+}
+
+func DeleteMemberAvatar(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "DeleteMemberAvatar", "member_id", id)
+	logger.Debug("Request started")
+
+	member, err := services.DeleteMemberAvatar(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Deleted member avatar", logging.Latency(start))
+	c.JSON(http.StatusOK, member)
+}
+
+func UploadTeamLogo(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UploadTeamLogo", "team_id", id)
+	logger.Debug("Request started")
+
+	data, ok := readUpload(c)
+	if !ok {
+		logger.Warn("Invalid upload")
+		return
+	}
+
+	team, err := services.SetTeamLogo(c.Request.Context(), id, data)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Uploaded team logo", "bytes", len(data), logging.Latency(start))
+	c.JSON(http.StatusOK, team)
+}
+
+func DeleteTeamLogo(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "DeleteTeamLogo", "team_id", id)
+	logger.Debug("Request started")
+
+	team, err := services.DeleteTeamLogo(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Deleted team logo", logging.Latency(start))
+	c.JSON(http.StatusOK, team)
+}
+
+func ServeMedia(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "ServeMedia", "key", key)
+
+	object, err := services.Blobs.Get(c.Request.Context(), key)
+	if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Image not found",
+			"message": "The requested image does not exist",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error("Storage error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Storage error",
+			"message": "Failed to read image",
+		})
+		return
+	}
+	defer object.Body.Close()
+
+	c.Header("Content-Type", object.ContentType)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", `"`+strings.TrimSuffix(path.Base(key), path.Ext(key))+`"`)
+	http.ServeContent(c.Writer, c.Request, "", object.ModTime, object.Body)
+}
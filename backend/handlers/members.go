@@ -1,47 +1,23 @@
 package handlers
 
 import (
-	"coaching-backend/database"
+	"coaching-backend/logging"
 	"coaching-backend/models"
-	"fmt"
+	"coaching-backend/services"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"log"
 	"net/http"
-	"regexp"
-	"strings"
+	"strconv"
 	"time"
 )
 
-func validateTeamMember(member *models.TeamMember) error {
-	if strings.TrimSpace(member.Name) == "" {
-		return fmt.Errorf("name is required")
-	}
-	if len(strings.TrimSpace(member.Name)) < 2 {
-		return fmt.Errorf("name must be at least 2 characters")
-	}
-	if len(strings.TrimSpace(member.Name)) > 50 {
-		return fmt.Errorf("name must be less than 50 characters")
-	}
-
-	if strings.TrimSpace(member.Email) == "" {
-		return fmt.Errorf("email is required")
-	}
-	emailRegex := regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
-	if !emailRegex.MatchString(strings.TrimSpace(member.Email)) {
-		return fmt.Errorf("invalid email format")
-	}
-
-	return nil
-}
-
 func CreateTeamMember(c *gin.Context) {
 	start := time.Now()
-	log.Printf("CreateTeamMember: Request started")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "CreateTeamMember")
+	logger.Debug("Request started")
 
 	var member models.TeamMember
 	if err := c.ShouldBindJSON(&member); err != nil {
-		log.Printf("CreateTeamMember: Invalid JSON - %v", err)
+		logger.Warn("Invalid JSON", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
 			"message": "Please check your input data",
@@ -49,62 +25,54 @@ func CreateTeamMember(c *gin.Context) {
 		return
 	}
 
-	if err := validateTeamMember(&member); err != nil {
-		log.Printf("CreateTeamMember: Validation failed - %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	member.ID = uuid.New().String()
-	member.Name = strings.TrimSpace(member.Name)
-	member.Email = strings.TrimSpace(member.Email)
-	member.Picture = strings.TrimSpace(member.Picture)
-
-	if err := database.DB.Create(&member).Error; err != nil {
-		log.Printf("CreateTeamMember: Database error - %v", err)
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			c.JSON(http.StatusConflict, gin.H{
-				"error":   "Member already exists",
-				"message": "A member with this email already exists",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Database error",
-				"message": "Failed to create team member",
-			})
-		}
+	if err := services.CreateMember(c.Request.Context(), &member); err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("CreateTeamMember: Successfully created member %s in %v", member.ID, time.Since(start))
+	logger.Info("Created member", "member_id", member.ID, logging.Latency(start))
 	c.JSON(http.StatusCreated, member)
 }
 
 func GetTeamMembers(c *gin.Context) {
 	start := time.Now()
-	log.Printf("GetTeamMembers: Request started")
-
-	var members []models.TeamMember
-	if err := database.DB.Find(&members).Error; err != nil {
-		log.Printf("GetTeamMembers: Database error - %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to fetch team members",
-		})
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetTeamMembers")
+	logger.Debug("Request started")
+
+	filter := services.MemberFilter{Skill: c.Query("skill"), Level: c.Query("level")}
+	if value := c.Query("min_proficiency"); value != "" {
+		proficiency, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "min_proficiency must be an integer",
+			})
+			return
+		}
+		filter.MinProficiency = proficiency
+	}
+
+	members, err := services.ListMembers(c.Request.Context(), filter)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("GetTeamMembers: Successfully fetched %d members in %v", len(members), time.Since(start))
+	for i := range members {
+		services.ResolveMemberPicture(&members[i])
+	}
+
+	logger.Debug("Fetched members", "count", len(members), logging.Latency(start))
 	c.JSON(http.StatusOK, members)
 }
 
 func GetTeamMember(c *gin.Context) {
 	start := time.Now()
 	id := c.Param("id")
-	log.Printf("GetTeamMember: Request started for ID %s", id)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetTeamMember", "member_id", id)
+	logger.Debug("Request started")
 
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -114,24 +82,24 @@ func GetTeamMember(c *gin.Context) {
 		return
 	}
 
-	var member models.TeamMember
-	if err := database.DB.First(&member, "id = ?", id).Error; err != nil {
-		log.Printf("GetTeamMember: Member not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Member not found",
-			"message": "The requested team member does not exist",
-		})
+	member, err := services.GetMember(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("GetTeamMember: Successfully fetched member %s in %v", id, time.Since(start))
+	services.ResolveMemberPicture(&member)
+
+	logger.Debug("Fetched member", logging.Latency(start))
 	c.JSON(http.StatusOK, member)
 }
 
 func UpdateTeamMember(c *gin.Context) {
 	start := time.Now()
 	id := c.Param("id")
-	log.Printf("UpdateTeamMember: Request started for ID %s", id)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UpdateTeamMember", "member_id", id)
+	logger.Debug("Request started")
 
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -141,19 +109,9 @@ func UpdateTeamMember(c *gin.Context) {
 		return
 	}
 
-	var member models.TeamMember
-	if err := database.DB.First(&member, "id = ?", id).Error; err != nil {
-		log.Printf("UpdateTeamMember: Member not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Member not found",
-			"message": "The requested team member does not exist",
-		})
-		return
-	}
-
 	var updateData models.TeamMember
 	if err := c.ShouldBindJSON(&updateData); err != nil {
-		log.Printf("UpdateTeamMember: Invalid JSON - %v", err)
+		logger.Warn("Invalid JSON", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
 			"message": "Please check your input data",
@@ -161,36 +119,22 @@ func UpdateTeamMember(c *gin.Context) {
 		return
 	}
 
-	if err := validateTeamMember(&updateData); err != nil {
-		log.Printf("UpdateTeamMember: Validation failed - %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	updateData.Name = strings.TrimSpace(updateData.Name)
-	updateData.Email = strings.TrimSpace(updateData.Email)
-	updateData.Picture = strings.TrimSpace(updateData.Picture)
-
-	if err := database.DB.Model(&member).Updates(updateData).Error; err != nil {
-		log.Printf("UpdateTeamMember: Database error - %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to update team member",
-		})
+	member, err := services.UpdateMember(c.Request.Context(), id, updateData)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("UpdateTeamMember: Successfully updated member %s in %v", id, time.Since(start))
+	logger.Info("Updated member", logging.Latency(start))
 	c.JSON(http.StatusOK, member)
 }
 
 func DeleteTeamMember(c *gin.Context) {
 	start := time.Now()
 	id := c.Param("id")
-	log.Printf("DeleteTeamMember: Request started for ID %s", id)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "DeleteTeamMember", "member_id", id)
+	logger.Debug("Request started")
 
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -200,25 +144,12 @@ func DeleteTeamMember(c *gin.Context) {
 		return
 	}
 
-	result := database.DB.Delete(&models.TeamMember{}, "id = ?", id)
-	if result.Error != nil {
-		log.Printf("DeleteTeamMember: Database error - %v", result.Error)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to delete team member",
-		})
-		return
-	}
-
-	if result.RowsAffected == 0 {
-		log.Printf("DeleteTeamMember: Member not found")
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Member not found",
-			"message": "The requested team member does not exist",
-		})
+	if err := services.DeleteMember(c.Request.Context(), id); err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("DeleteTeamMember: Successfully deleted member %s in %v", id, time.Since(start))
+	logger.Info("Deleted member", logging.Latency(start))
 	c.JSON(http.StatusOK, gin.H{"message": "Team member deleted successfully"})
 }
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+func GetNotificationPreferences(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetNotificationPreferences", "id", id)
+	logger.Debug("Request started")
+
+	var member models.TeamMember
+	if err := database.DB.WithContext(c.Request.Context()).First(&member, "id = ?", id).Error; err != nil {
+		logger.Warn("Member not found", "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Member not found",
+			"message": "The requested team member does not exist",
+		})
+		return
+	}
+
+	preferences := models.NotificationPreference{MemberID: id}
+	if err := database.DB.WithContext(c.Request.Context()).Where("member_id = ?", id).Limit(1).Find(&preferences).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to fetch notification preferences",
+		})
+		return
+	}
+
+	logger.Debug("Fetched notification preferences", logging.Latency(start))
+	c.JSON(http.StatusOK, preferences)
+}
+
+func UpdateNotificationPreferences(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UpdateNotificationPreferences", "id", id)
+	logger.Debug("Request started")
+
+	var member models.TeamMember
+	if err := database.DB.WithContext(c.Request.Context()).First(&member, "id = ?", id).Error; err != nil {
+		logger.Warn("Member not found", "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Member not found",
+			"message": "The requested team member does not exist",
+		})
+		return
+	}
+
+	var updateData models.NotificationPreference
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Please check your input data",
+		})
+		return
+	}
+
+	preferences := models.NotificationPreference{MemberID: id}
+	if err := database.DB.WithContext(c.Request.Context()).Where("member_id = ?", id).Limit(1).Find(&preferences).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to fetch notification preferences",
+		})
+		return
+	}
+
+	preferences.EmailOptOut = updateData.EmailOptOut
+	preferences.TeamFeedbackOptOut = updateData.TeamFeedbackOptOut
+
+	if err := database.DB.WithContext(c.Request.Context()).Save(&preferences).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to update notification preferences",
+		})
+		return
+	}
+
+	logger.Info("Updated notification preferences", logging.Latency(start))
+	c.JSON(http.StatusOK, preferences)
+}
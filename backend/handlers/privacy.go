@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"coaching-backend/logging"
+	"coaching-backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+func ExportTeamMember(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "ExportTeamMember", "member_id", id)
+	logger.Debug("Request started")
+
+	export, err := services.ExportMember(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Exported member data", "feedback_received", len(export.FeedbackReceived), "feedback_given", len(export.FeedbackGiven), "audit_entries", len(export.AuditEntries), "client_ip", c.ClientIP(), logging.Latency(start))
+	c.Header("Content-Disposition", `attachment; filename="member-`+id+`.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+func EraseTeamMember(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "EraseTeamMember", "member_id", id)
+	logger.Debug("Request started")
+
+	receipt, err := services.EraseMember(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Erased member", "receipt_id", receipt.ID, "feedback_anonymized", receipt.FeedbackAnonymized, "client_ip", c.ClientIP(), logging.Latency(start))
+	c.JSON(http.StatusOK, receipt)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+func GetReminders(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetReminders")
+	logger.Debug("Request started")
+
+	var reminders []models.Reminder
+
+	status := c.DefaultQuery("status", "open")
+	targetType := c.Query("target_type")
+
+	query := database.DB.WithContext(c.Request.Context())
+	if status != "all" {
+		if status != "open" && status != "resolved" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "status must be one of 'open', 'resolved' or 'all'",
+			})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	if targetType != "" {
+		if targetType != "team" && targetType != "member" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "target_type must be either 'team' or 'member'",
+			})
+			return
+		}
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	if err := query.Order("created_at DESC").Find(&reminders).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to fetch reminders",
+		})
+		return
+	}
+
+	logger.Debug("Fetched reminders", "count", len(reminders), logging.Latency(start))
+	c.JSON(http.StatusOK, reminders)
+}
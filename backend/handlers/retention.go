@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/logging"
+	"coaching-backend/retention"
+	"coaching-backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type LegalHoldRequest struct {
+	LegalHold *bool `json:"legal_hold" binding:"required"`
+}
+
+func retentionConfig() config.Retention {
+	if Config == nil {
+		return config.Default().Retention
+	}
+	return Config.Retention
+}
+
+func GetRetentionReport(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetRetentionReport")
+	logger.Debug("Request started")
+
+	cfg := retentionConfig()
+	report, err := retention.Enforce(c.Request.Context(), cfg.ParsedPolicies(), time.Now(), cfg.BatchSize, true)
+	if err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to evaluate retention policies",
+		})
+		return
+	}
+
+	logger.Debug("Evaluated retention policies", "policies", len(report.Policies), logging.Latency(start))
+	c.JSON(http.StatusOK, report)
+}
+
+func RunRetention(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "RunRetention")
+	logger.Debug("Request started")
+
+	cfg := retentionConfig()
+	dryRun := cfg.DryRun
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "dry_run must be true or false",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := retention.Enforce(c.Request.Context(), cfg.ParsedPolicies(), time.Now(), cfg.BatchSize, dryRun)
+	if err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to apply retention policies",
+		})
+		return
+	}
+
+	logger.Info("Applied retention policies", "dry_run", dryRun, "client_ip", c.ClientIP(), logging.Latency(start))
+	c.JSON(http.StatusOK, report)
+}
+
+func SetMemberLegalHold(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "SetMemberLegalHold", "member_id", id)
+	logger.Debug("Request started")
+
+	var request LegalHoldRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "legal_hold is required",
+		})
+		return
+	}
+
+	member, err := services.SetMemberLegalHold(c.Request.Context(), id, *request.LegalHold)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Updated member legal hold", "legal_hold", member.LegalHold, "client_ip", c.ClientIP(), logging.Latency(start))
+	c.JSON(http.StatusOK, member)
+}
+
+func SetFeedbackLegalHold(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "SetFeedbackLegalHold", "feedback_id", id)
+	logger.Debug("Request started")
+
+	var request LegalHoldRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "legal_hold is required",
+		})
+		return
+	}
+
+	feedback, err := services.SetFeedbackLegalHold(c.Request.Context(), id, *request.LegalHold)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
+		return
+	}
+
+	logger.Info("Updated feedback legal hold", "legal_hold", feedback.LegalHold, "client_ip", c.ClientIP(), logging.Latency(start))
+	c.JSON(http.StatusOK, feedback)
+}
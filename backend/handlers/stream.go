@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"coaching-backend/events"
+	"coaching-backend/logging"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func splitQueryList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func StreamEvents(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "StreamEvents")
+	logger.Debug("Request started")
+
+	filter := events.Filter{
+		Entities: splitQueryList(c.Query("entities")),
+		Types:    splitQueryList(c.Query("types")),
+	}
+	for _, entity := range filter.Entities {
+		if entity != "team" && entity != "member" && entity != "feedback" && entity != "goal" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "entities must contain only 'team', 'member', 'feedback' or 'goal'",
+			})
+			return
+		}
+	}
+	for _, eventType := range filter.Types {
+		if !events.IsValidType(eventType) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "unknown event type '" + eventType + "'",
+			})
+			return
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var lastSeq uint64
+	if lastEventID != "" {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid parameter",
+				"message": "Last-Event-ID must be a numeric event ID",
+			})
+			return
+		}
+		lastSeq = parsed
+	}
+
+	sub, backlog, complete := events.Stream.Subscribe(lastSeq, filter)
+	defer events.Stream.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	if !complete {
+		c.Render(-1, sse.Event{
+			Event: "stream.reset",
+			Data:  gin.H{"message": "Some events are no longer available, please reload all data"},
+		})
+	}
+	for _, entry := range backlog {
+		renderEntry(c, entry)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	sent := len(backlog)
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-closing:
+			return false
+		case entry, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			renderEntry(c, entry)
+			sent++
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+
+	logger.Info("Stream closed", "events_sent", sent, logging.Latency(start))
+}
+
+func renderEntry(c *gin.Context, entry events.Entry) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(entry.Seq, 10),
+		Event: entry.Event.Type,
+		Data:  entry.Event,
+	})
+}
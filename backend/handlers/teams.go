@@ -1,37 +1,22 @@
 package handlers
 
 import (
-	"coaching-backend/database"
+	"coaching-backend/logging"
 	"coaching-backend/models"
-	"fmt"
+	"coaching-backend/services"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"log"
 	"net/http"
-	"strings"
 	"time"
 )
 
-func validateTeam(team *models.Team) error {
-	if strings.TrimSpace(team.Name) == "" {
-		return fmt.Errorf("team name is required")
-	}
-	if len(strings.TrimSpace(team.Name)) < 2 {
-		return fmt.Errorf("team name must be at least 2 characters")
-	}
-	if len(strings.TrimSpace(team.Name)) > 50 {
-		return fmt.Errorf("team name must be less than 50 characters")
-	}
-	return nil
-}
-
 func CreateTeam(c *gin.Context) {
 	start := time.Now()
-	log.Printf("CreateTeam: Request started")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "CreateTeam")
+	logger.Debug("Request started")
 
 	var team models.Team
 	if err := c.ShouldBindJSON(&team); err != nil {
-		log.Printf("CreateTeam: Invalid JSON - %v", err)
+		logger.Warn("Invalid JSON", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
 			"message": "Please check your input data",
@@ -39,61 +24,41 @@ func CreateTeam(c *gin.Context) {
 		return
 	}
 
-	if err := validateTeam(&team); err != nil {
-		log.Printf("CreateTeam: Validation failed - %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	team.ID = uuid.New().String()
-	team.Name = strings.TrimSpace(team.Name)
-	team.Logo = strings.TrimSpace(team.Logo)
-
-	if err := database.DB.Create(&team).Error; err != nil {
-		log.Printf("CreateTeam: Database error - %v", err)
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			c.JSON(http.StatusConflict, gin.H{
-				"error":   "Team already exists",
-				"message": "A team with this name already exists",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Database error",
-				"message": "Failed to create team",
-			})
-		}
+	if err := services.CreateTeam(c.Request.Context(), &team); err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("CreateTeam: Successfully created team %s in %v", team.ID, time.Since(start))
+	logger.Info("Created team", "team_id", team.ID, logging.Latency(start))
 	c.JSON(http.StatusCreated, team)
 }
 
 func GetTeams(c *gin.Context) {
 	start := time.Now()
-	log.Printf("GetTeams: Request started")
-
-	var teams []models.Team
-	if err := database.DB.Preload("Members").Find(&teams).Error; err != nil {
-		log.Printf("GetTeams: Database error - %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to fetch teams",
-		})
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetTeams")
+	logger.Debug("Request started")
+
+	teams, err := services.ListTeams(c.Request.Context())
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("GetTeams: Successfully fetched %d teams in %v", len(teams), time.Since(start))
+	for i := range teams {
+		services.ResolveTeamLogo(&teams[i])
+	}
+
+	logger.Debug("Fetched teams", "count", len(teams), logging.Latency(start))
 	c.JSON(http.StatusOK, teams)
 }
 
 func GetTeam(c *gin.Context) {
 	start := time.Now()
 	id := c.Param("id")
-	log.Printf("GetTeam: Request started for ID %s", id)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetTeam", "team_id", id)
+	logger.Debug("Request started")
 
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -103,24 +68,24 @@ func GetTeam(c *gin.Context) {
 		return
 	}
 
-	var team models.Team
-	if err := database.DB.Preload("Members").First(&team, "id = ?", id).Error; err != nil {
-		log.Printf("GetTeam: Team not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Team not found",
-			"message": "The requested team does not exist",
-		})
+	team, err := services.GetTeam(c.Request.Context(), id)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("GetTeam: Successfully fetched team %s in %v", id, time.Since(start))
+	services.ResolveTeamLogo(&team)
+
+	logger.Debug("Fetched team", logging.Latency(start))
 	c.JSON(http.StatusOK, team)
 }
 
 func UpdateTeam(c *gin.Context) {
 	start := time.Now()
 	id := c.Param("id")
-	log.Printf("UpdateTeam: Request started for ID %s", id)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UpdateTeam", "team_id", id)
+	logger.Debug("Request started")
 
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -130,19 +95,9 @@ func UpdateTeam(c *gin.Context) {
 		return
 	}
 
-	var team models.Team
-	if err := database.DB.First(&team, "id = ?", id).Error; err != nil {
-		log.Printf("UpdateTeam: Team not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Team not found",
-			"message": "The requested team does not exist",
-		})
-		return
-	}
-
 	var updateData models.Team
 	if err := c.ShouldBindJSON(&updateData); err != nil {
-		log.Printf("UpdateTeam: Invalid JSON - %v", err)
+		logger.Warn("Invalid JSON", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
 			"message": "Please check your input data",
@@ -150,35 +105,22 @@ func UpdateTeam(c *gin.Context) {
 		return
 	}
 
-	if err := validateTeam(&updateData); err != nil {
-		log.Printf("UpdateTeam: Validation failed - %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"message": err.Error(),
-		})
+	team, err := services.UpdateTeam(c.Request.Context(), id, updateData)
+	if err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	updateData.Name = strings.TrimSpace(updateData.Name)
-	updateData.Logo = strings.TrimSpace(updateData.Logo)
-
-	if err := database.DB.Model(&team).Updates(updateData).Error; err != nil {
-		log.Printf("UpdateTeam: Database error - %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to update team",
-		})
-		return
-	}
-
-	log.Printf("UpdateTeam: Successfully updated team %s in %v", id, time.Since(start))
+	logger.Info("Updated team", logging.Latency(start))
 	c.JSON(http.StatusOK, team)
 }
 
 func DeleteTeam(c *gin.Context) {
 	start := time.Now()
 	id := c.Param("id")
-	log.Printf("DeleteTeam: Request started for ID %s", id)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "DeleteTeam", "team_id", id)
+	logger.Debug("Request started")
 
 	if id == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -188,41 +130,30 @@ func DeleteTeam(c *gin.Context) {
 		return
 	}
 
-	result := database.DB.Delete(&models.Team{}, "id = ?", id)
-	if result.Error != nil {
-		log.Printf("DeleteTeam: Database error - %v", result.Error)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to delete team",
-		})
-		return
-	}
-
-	if result.RowsAffected == 0 {
-		log.Printf("DeleteTeam: Team not found")
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Team not found",
-			"message": "The requested team does not exist",
-		})
+	if err := services.DeleteTeam(c.Request.Context(), id); err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("DeleteTeam: Successfully deleted team %s in %v", id, time.Since(start))
+	logger.Info("Deleted team", logging.Latency(start))
 	c.JSON(http.StatusOK, gin.H{"message": "Team deleted successfully"})
 }
 
 type AssignRequest struct {
-	MemberID string `json:"member_id" binding:"required"`
-	TeamID   string `json:"team_id" binding:"required"`
+	MemberID       string  `json:"member_id" binding:"required"`
+	TeamID         string  `json:"team_id" binding:"required"`
+	ExpectedTeamID *string `json:"expected_team_id,omitempty"`
 }
 
 func AssignMemberToTeam(c *gin.Context) {
 	start := time.Now()
-	log.Printf("AssignMemberToTeam: Request started")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "AssignMemberToTeam")
+	logger.Debug("Request started")
 
 	var req AssignRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("AssignMemberToTeam: Invalid JSON - %v", err)
+		logger.Warn("Invalid JSON", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request format",
 			"message": "Member ID and Team ID are required",
@@ -230,44 +161,21 @@ func AssignMemberToTeam(c *gin.Context) {
 		return
 	}
 
-	var member models.TeamMember
-	if err := database.DB.First(&member, "id = ?", req.MemberID).Error; err != nil {
-		log.Printf("AssignMemberToTeam: Member not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Member not found",
-			"message": "The requested team member does not exist",
-		})
+	if _, err := services.AssignMember(c.Request.Context(), req.MemberID, req.TeamID, req.ExpectedTeamID); err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	var team models.Team
-	if err := database.DB.First(&team, "id = ?", req.TeamID).Error; err != nil {
-		log.Printf("AssignMemberToTeam: Team not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Team not found",
-			"message": "The requested team does not exist",
-		})
-		return
-	}
-
-	member.TeamID = &req.TeamID
-	if err := database.DB.Save(&member).Error; err != nil {
-		log.Printf("AssignMemberToTeam: Database error - %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to assign member to team",
-		})
-		return
-	}
-
-	log.Printf("AssignMemberToTeam: Successfully assigned member %s to team %s in %v", req.MemberID, req.TeamID, time.Since(start))
+	logger.Info("Assigned member to team", "member_id", req.MemberID, "team_id", req.TeamID, logging.Latency(start))
 	c.JSON(http.StatusOK, gin.H{"message": "Member assigned to team successfully"})
 }
 
 func RemoveMemberFromTeam(c *gin.Context) {
 	start := time.Now()
 	memberID := c.Param("memberID")
-	log.Printf("RemoveMemberFromTeam: Request started for member ID %s", memberID)
+	logger := logging.FromContext(c.Request.Context()).With("handler", "RemoveMemberFromTeam", "member_id", memberID)
+	logger.Debug("Request started")
 
 	if memberID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -277,26 +185,17 @@ func RemoveMemberFromTeam(c *gin.Context) {
 		return
 	}
 
-	var member models.TeamMember
-	if err := database.DB.First(&member, "id = ?", memberID).Error; err != nil {
-		log.Printf("RemoveMemberFromTeam: Member not found - %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Member not found",
-			"message": "The requested team member does not exist",
-		})
-		return
+	var expectedTeamID *string
+	if value, ok := c.GetQuery("expected_team_id"); ok {
+		expectedTeamID = &value
 	}
 
-	member.TeamID = nil
-	if err := database.DB.Save(&member).Error; err != nil {
-		log.Printf("RemoveMemberFromTeam: Database error - %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Database error",
-			"message": "Failed to remove member from team",
-		})
+	if _, err := services.RemoveMember(c.Request.Context(), memberID, expectedTeamID); err != nil {
+		logError(logger, err)
+		respondError(c, err)
 		return
 	}
 
-	log.Printf("RemoveMemberFromTeam: Successfully removed member %s from team in %v", memberID, time.Since(start))
+	logger.Info("Removed member from team", logging.Latency(start))
 	c.JSON(http.StatusOK, gin.H{"message": "Member removed from team successfully"})
 }
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"coaching-backend/webhooks"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func validateWebhook(subscription *models.WebhookSubscription) error {
+	parsed, err := url.Parse(strings.TrimSpace(subscription.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be a valid http or https URL")
+	}
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil {
+		if err := webhooks.CheckIP(ip); err != nil {
+			return fmt.Errorf("url must point to a public address")
+		}
+	}
+	if len(subscription.Events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	for _, e := range subscription.Events {
+		if e != "*" && !events.IsValidType(e) {
+			return fmt.Errorf("unknown event type '%s'", e)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func CreateWebhook(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "CreateWebhook")
+	logger.Debug("Request started")
+
+	var subscription models.WebhookSubscription
+	if err := c.ShouldBindJSON(&subscription); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Please check your input data",
+		})
+		return
+	}
+
+	if err := validateWebhook(&subscription); err != nil {
+		logger.Warn("Validation failed", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	subscription.ID = uuid.New().String()
+	subscription.URL = strings.TrimSpace(subscription.URL)
+	if subscription.Active == nil {
+		active := true
+		subscription.Active = &active
+	}
+	if strings.TrimSpace(subscription.Secret) == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			logger.Error("Secret generation failed", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal error",
+				"message": "Failed to generate webhook secret",
+			})
+			return
+		}
+		subscription.Secret = secret
+	}
+
+	if err := database.DB.WithContext(c.Request.Context()).Create(&subscription).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to create webhook",
+		})
+		return
+	}
+
+	webhooks.InvalidateSubscriptions()
+
+	logger.Info("Created webhook", "webhook_id", subscription.ID, logging.Latency(start))
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func GetWebhooks(c *gin.Context) {
+	start := time.Now()
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetWebhooks")
+	logger.Debug("Request started")
+
+	var subscriptions []models.WebhookSubscription
+	if err := database.DB.WithContext(c.Request.Context()).Order("created_at DESC").Find(&subscriptions).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to fetch webhooks",
+		})
+		return
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	logger.Debug("Fetched webhooks", "count", len(subscriptions), logging.Latency(start))
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func GetWebhook(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetWebhook", "webhook_id", id)
+	logger.Debug("Request started")
+
+	var subscription models.WebhookSubscription
+	if err := database.DB.WithContext(c.Request.Context()).First(&subscription, "id = ?", id).Error; err != nil {
+		logger.Warn("Webhook not found", "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Webhook not found",
+			"message": "The requested webhook does not exist",
+		})
+		return
+	}
+
+	subscription.Secret = ""
+
+	logger.Debug("Fetched webhook", logging.Latency(start))
+	c.JSON(http.StatusOK, subscription)
+}
+
+func UpdateWebhook(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "UpdateWebhook", "webhook_id", id)
+	logger.Debug("Request started")
+
+	var subscription models.WebhookSubscription
+	if err := database.DB.WithContext(c.Request.Context()).First(&subscription, "id = ?", id).Error; err != nil {
+		logger.Warn("Webhook not found", "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Webhook not found",
+			"message": "The requested webhook does not exist",
+		})
+		return
+	}
+
+	var updateData models.WebhookSubscription
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		logger.Warn("Invalid JSON", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"message": "Please check your input data",
+		})
+		return
+	}
+
+	if err := validateWebhook(&updateData); err != nil {
+		logger.Warn("Validation failed", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	subscription.URL = strings.TrimSpace(updateData.URL)
+	subscription.Events = updateData.Events
+	if updateData.Active != nil {
+		subscription.Active = updateData.Active
+	}
+	if strings.TrimSpace(updateData.Secret) != "" {
+		subscription.Secret = strings.TrimSpace(updateData.Secret)
+	}
+
+	if err := database.DB.WithContext(c.Request.Context()).Save(&subscription).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to update webhook",
+		})
+		return
+	}
+
+	webhooks.InvalidateSubscriptions()
+	subscription.Secret = ""
+
+	logger.Info("Updated webhook", logging.Latency(start))
+	c.JSON(http.StatusOK, subscription)
+}
+
+func DeleteWebhook(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "DeleteWebhook", "webhook_id", id)
+	logger.Debug("Request started")
+
+	result := database.DB.WithContext(c.Request.Context()).Delete(&models.WebhookSubscription{}, "id = ?", id)
+	if result.Error != nil {
+		logger.Error("Database error", "error", result.Error)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to delete webhook",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Warn("Webhook not found")
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Webhook not found",
+			"message": "The requested webhook does not exist",
+		})
+		return
+	}
+
+	webhooks.InvalidateSubscriptions()
+
+	logger.Info("Deleted webhook", logging.Latency(start))
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func GetWebhookDeliveries(c *gin.Context) {
+	start := time.Now()
+	id := c.Param("id")
+	logger := logging.FromContext(c.Request.Context()).With("handler", "GetWebhookDeliveries", "webhook_id", id)
+	logger.Debug("Request started")
+
+	var subscription models.WebhookSubscription
+	if err := database.DB.WithContext(c.Request.Context()).First(&subscription, "id = ?", id).Error; err != nil {
+		logger.Warn("Webhook not found", "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Webhook not found",
+			"message": "The requested webhook does not exist",
+		})
+		return
+	}
+
+	query := database.DB.WithContext(c.Request.Context()).Where("subscription_id = ?", id)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(100).Find(&deliveries).Error; err != nil {
+		logger.Error("Database error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Failed to fetch webhook deliveries",
+		})
+		return
+	}
+
+	logger.Debug("Fetched deliveries", "count", len(deliveries), logging.Latency(start))
+	c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,220 @@
+package health
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/logging"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+type Check struct {
+	Status     string   `json:"status" binding:"required"`
+	Error      string   `json:"error,omitempty"`
+	LatencyMS  int64    `json:"latency_ms,omitempty"`
+	Pending    []string `json:"pending,omitempty"`
+	InUse      int      `json:"in_use,omitempty"`
+	Open       int      `json:"open,omitempty"`
+	MaxOpen    int      `json:"max_open,omitempty"`
+	WaitCount  int64    `json:"wait_count,omitempty"`
+	Saturation float64  `json:"saturation,omitempty"`
+}
+
+type Report struct {
+	Status    string           `json:"status" binding:"required"`
+	Version   string           `json:"version"`
+	Timestamp string           `json:"timestamp"`
+	Uptime    int64            `json:"uptime_seconds"`
+	Checks    map[string]Check `json:"checks,omitempty"`
+}
+
+var (
+	ReadinessTimeout = 2 * time.Second
+	PoolSaturation   = 0.9
+
+	started    = time.Now()
+	draining   atomic.Bool
+	migrations atomic.Pointer[Check]
+)
+
+func SetDraining(value bool) {
+	draining.Store(value)
+}
+
+func Health(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    StatusOK,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"version":   Version(),
+	})
+}
+
+func Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, report(StatusOK, nil))
+}
+
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ReadinessTimeout)
+	defer cancel()
+
+	checks := map[string]Check{
+		"database":   checkDatabase(ctx),
+		"migrations": migrationStatus(ctx),
+		"db_pool":    checkPool(ctx),
+	}
+	if draining.Load() {
+		checks["shutdown"] = Check{Status: StatusFail, Error: "server is shutting down"}
+	}
+
+	status := StatusOK
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			status = StatusFail
+			break
+		}
+		if check.Status == StatusWarn {
+			status = StatusWarn
+		}
+	}
+
+	code := http.StatusOK
+	if status == StatusFail {
+		code = http.StatusServiceUnavailable
+		logging.FromContext(ctx).Warn("Readiness check failed", "checks", checks)
+	}
+	c.JSON(code, report(status, checks))
+}
+
+func report(status string, checks map[string]Check) Report {
+	return Report{
+		Status:    status,
+		Version:   Version(),
+		Timestamp: time.Now().Format(time.RFC3339),
+		Uptime:    int64(time.Since(started).Seconds()),
+		Checks:    checks,
+	}
+}
+
+func checkDatabase(ctx context.Context) Check {
+	if database.DB == nil {
+		return Check{Status: StatusFail, Error: "database not connected"}
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		logging.FromContext(ctx).Error("Database handle unavailable", "error", err)
+		return Check{Status: StatusFail, Error: "database unavailable"}
+	}
+
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		logging.FromContext(ctx).Error("Database ping failed", "error", err)
+		return Check{Status: StatusFail, Error: "database unavailable", LatencyMS: latency}
+	}
+	return Check{Status: StatusOK, LatencyMS: latency}
+}
+
+func CheckMigrations(ctx context.Context) Check {
+	check := checkMigrations(ctx)
+	migrations.Store(&check)
+	return check
+}
+
+func migrationStatus(ctx context.Context) Check {
+	if check := migrations.Load(); check != nil {
+		return *check
+	}
+	return CheckMigrations(ctx)
+}
+
+func checkMigrations(ctx context.Context) Check {
+	if database.DB == nil {
+		return Check{Status: StatusFail, Error: "database not connected"}
+	}
+	db := database.DB.WithContext(ctx)
+
+	var pending []string
+	for _, model := range models.All() {
+		missing, err := pendingColumns(db, model)
+		if err != nil {
+			logging.FromContext(ctx).Error("Migration check failed", "error", err)
+			return Check{Status: StatusFail, Error: "migration check failed"}
+		}
+		pending = append(pending, missing...)
+	}
+	if len(pending) > 0 {
+		return Check{Status: StatusFail, Error: "schema is behind the models", Pending: pending}
+	}
+	return Check{Status: StatusOK}
+}
+
+func pendingColumns(db *gorm.DB, model interface{}) ([]string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	table := stmt.Schema.Table
+
+	if !db.Migrator().HasTable(model) {
+		if err := db.Statement.Context.Err(); err != nil {
+			return nil, err
+		}
+		return []string{table}, nil
+	}
+
+	columns, err := db.Migrator().ColumnTypes(model)
+	if err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	existing := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		existing[column.Name()] = true
+	}
+
+	var missing []string
+	for _, name := range stmt.Schema.DBNames {
+		if !existing[name] {
+			missing = append(missing, table+"."+name)
+		}
+	}
+	return missing, nil
+}
+
+func checkPool(ctx context.Context) Check {
+	if database.DB == nil {
+		return Check{Status: StatusFail, Error: "database not connected"}
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		logging.FromContext(ctx).Error("Database handle unavailable", "error", err)
+		return Check{Status: StatusFail, Error: "database unavailable"}
+	}
+
+	stats := sqlDB.Stats()
+	check := Check{
+		Status:    StatusOK,
+		InUse:     stats.InUse,
+		Open:      stats.OpenConnections,
+		MaxOpen:   stats.MaxOpenConnections,
+		WaitCount: stats.WaitCount,
+	}
+	if stats.MaxOpenConnections > 0 {
+		check.Saturation = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+		if check.Saturation >= PoolSaturation {
+			check.Status = StatusWarn
+		}
+	}
+	return check
+}
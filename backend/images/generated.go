@@ -0,0 +1,250 @@
+package images
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinGeneratedSize     = 16
+	MaxGeneratedSize     = 512
+	DefaultGeneratedSize = 128
+	identiconCells       = 5
+)
+
+func Initials(name string) string {
+	var letters []rune
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				letters = append(letters, unicode.ToUpper(r))
+				break
+			}
+		}
+	}
+	switch len(letters) {
+	case 0:
+		return "?"
+	case 1:
+		return string(letters[0])
+	}
+	return string([]rune{letters[0], letters[len(letters)-1]})
+}
+
+func InitialsSVG(name string, size int) []byte {
+	background := seedColor(strings.ToLower(strings.TrimSpace(name)))
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, size, size, size, size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, size, size, hexColor(background))
+	fmt.Fprintf(&buf, `<text x="50%%" y="50%%" dy=".35em" text-anchor="middle" fill="#ffffff" font-family="Helvetica, Arial, sans-serif" font-size="%d" font-weight="600">%s</text>`, size*2/5, html.EscapeString(Initials(name)))
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+func InitialsPNG(name string, size int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	fill(img, img.Bounds(), seedColor(strings.ToLower(strings.TrimSpace(name))))
+
+	var text []rune
+	for _, r := range Initials(name) {
+		text = append(text, glyphRune(r))
+	}
+	width := len(text)*(glyphWidth+1) - 1
+	scale := max(1, size/2/width)
+	left := (size - width*scale) / 2
+	top := (size - glyphHeight*scale) / 2
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	for i, r := range text {
+		for row, line := range glyphs[r] {
+			for col, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				x := left + (i*(glyphWidth+1)+col)*scale
+				y := top + row*scale
+				fill(img, image.Rect(x, y, x+scale, y+scale), white)
+			}
+		}
+	}
+	return encodePNG(img)
+}
+
+func identicon(seed string) ([identiconCells][identiconCells]bool, color.RGBA) {
+	sum := sha256.Sum256([]byte(seed))
+	var cells [identiconCells][identiconCells]bool
+	bit := 0
+	for col := 0; col < (identiconCells+1)/2; col++ {
+		for row := 0; row < identiconCells; row++ {
+			on := sum[4+bit/8]&(1<<(bit%8)) != 0
+			cells[row][col] = on
+			cells[row][identiconCells-1-col] = on
+			bit++
+		}
+	}
+	return cells, hueColor(binary.BigEndian.Uint32(sum[:4]))
+}
+
+func identiconLayout(size int) (float64, float64) {
+	cell := float64(size) / (identiconCells + 1)
+	return cell, cell / 2
+}
+
+func IdenticonSVG(seed string, size int) []byte {
+	cells, foreground := identicon(seed)
+	cell, margin := identiconLayout(size)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, size, size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#f0f0f0"/>`, size, size)
+	for row := range cells {
+		for col, on := range cells[row] {
+			if on {
+				fmt.Fprintf(&buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`,
+					margin+float64(col)*cell, margin+float64(row)*cell, cell, cell, hexColor(foreground))
+			}
+		}
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+func IdenticonPNG(seed string, size int) ([]byte, error) {
+	cells, foreground := identicon(seed)
+	cell, margin := identiconLayout(size)
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	fill(img, img.Bounds(), color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 255})
+	for row := range cells {
+		for col, on := range cells[row] {
+			if !on {
+				continue
+			}
+			x0 := int(math.Round(margin + float64(col)*cell))
+			y0 := int(math.Round(margin + float64(row)*cell))
+			x1 := int(math.Round(margin + float64(col+1)*cell))
+			y1 := int(math.Round(margin + float64(row+1)*cell))
+			fill(img, image.Rect(x0, y0, x1, y1), foreground)
+		}
+	}
+	return encodePNG(img)
+}
+
+func fill(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func seedColor(seed string) color.RGBA {
+	sum := sha256.Sum256([]byte(seed))
+	return hueColor(binary.BigEndian.Uint32(sum[:4]))
+}
+
+func hueColor(seed uint32) color.RGBA {
+	hue := float64(seed%360) / 60
+	const saturation, lightness = 0.55, 0.45
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g = chroma, x
+	case 1:
+		r, g = x, chroma
+	case 2:
+		g, b = chroma, x
+	case 3:
+		g, b = x, chroma
+	case 4:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := lightness - chroma/2
+	return color.RGBA{
+		R: uint8(math.Round((r + m) * 255)),
+		G: uint8(math.Round((g + m) * 255)),
+		B: uint8(math.Round((b + m) * 255)),
+		A: 255,
+	}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func glyphRune(r rune) rune {
+	if _, ok := glyphs[r]; ok {
+		return r
+	}
+	base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+	if _, ok := glyphs[unicode.ToUpper(base)]; ok {
+		return unicode.ToUpper(base)
+	}
+	return '?'
+}
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+var glyphs = map[rune][glyphHeight]string{
+	'A': {" ### ", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'B': {"#### ", "#   #", "#   #", "#### ", "#   #", "#   #", "#### "},
+	'C': {" ### ", "#   #", "#    ", "#    ", "#    ", "#   #", " ### "},
+	'D': {"#### ", "#   #", "#   #", "#   #", "#   #", "#   #", "#### "},
+	'E': {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#####"},
+	'F': {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#    "},
+	'G': {" ### ", "#   #", "#    ", "# ###", "#   #", "#   #", " ####"},
+	'H': {"#   #", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'I': {" ### ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'J': {"  ###", "   # ", "   # ", "   # ", "   # ", "#  # ", " ##  "},
+	'K': {"#   #", "#  # ", "# #  ", "##   ", "# #  ", "#  # ", "#   #"},
+	'L': {"#    ", "#    ", "#    ", "#    ", "#    ", "#    ", "#####"},
+	'M': {"#   #", "## ##", "# # #", "# # #", "#   #", "#   #", "#   #"},
+	'N': {"#   #", "#   #", "##  #", "# # #", "#  ##", "#   #", "#   #"},
+	'O': {" ### ", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'P': {"#### ", "#   #", "#   #", "#### ", "#    ", "#    ", "#    "},
+	'Q': {" ### ", "#   #", "#   #", "#   #", "# # #", "#  # ", " ## #"},
+	'R': {"#### ", "#   #", "#   #", "#### ", "# #  ", "#  # ", "#   #"},
+	'S': {" ####", "#    ", "#    ", " ### ", "    #", "    #", "#### "},
+	'T': {"#####", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U': {"#   #", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'V': {"#   #", "#   #", "#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W': {"#   #", "#   #", "#   #", "# # #", "# # #", "# # #", " # # "},
+	'X': {"#   #", "#   #", " # # ", "  #  ", " # # ", "#   #", "#   #"},
+	'Y': {"#   #", "#   #", " # # ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'Z': {"#####", "    #", "   # ", "  #  ", " #   ", "#    ", "#####"},
+	'0': {" ### ", "#   #", "#  ##", "# # #", "##  #", "#   #", " ### "},
+	'1': {"  #  ", " ##  ", "  #  ", "  #  ", "  #  ", "  #  ", " ### "},
+	'2': {" ### ", "#   #", "    #", "   # ", "  #  ", " #   ", "#####"},
+	'3': {"#####", "   # ", "  #  ", "   # ", "    #", "#   #", " ### "},
+	'4': {"   # ", "  ## ", " # # ", "#  # ", "#####", "   # ", "   # "},
+	'5': {"#####", "#    ", "#### ", "    #", "    #", "#   #", " ### "},
+	'6': {"  ## ", " #   ", "#    ", "#### ", "#   #", "#   #", " ### "},
+	'7': {"#####", "    #", "   # ", "  #  ", " #   ", " #   ", " #   "},
+	'8': {" ### ", "#   #", "#   #", " ### ", "#   #", "#   #", " ### "},
+	'9': {" ### ", "#   #", "#   #", " ####", "    #", "   # ", " ##  "},
+	'?': {" ### ", "#   #", "    #", "   # ", "  #  ", "     ", "  #  "},
+}
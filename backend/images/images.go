@@ -0,0 +1,266 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+)
+
+const (
+	MaxDimension = 4096
+	MaxPixels    = 4096 * 3072
+)
+
+var (
+	Sizes = []int{64, 128, 256}
+
+	ErrUnsupported = errors.New("unsupported image type, upload a JPEG, PNG or GIF")
+	ErrTooLarge    = fmt.Errorf("image must be at most %d pixels on either side and %d megapixels", MaxDimension, MaxPixels/1000000)
+	ErrInvalid     = errors.New("the file is not a valid image")
+
+	decodeSlots = make(chan struct{}, 2)
+)
+
+type Thumbnail struct {
+	Size        int
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+func DetectType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return contentType, nil
+	}
+	return contentType, ErrUnsupported
+}
+
+func Thumbnails(data []byte, sizes []int) ([]Thumbnail, error) {
+	contentType, err := DetectType(data)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return nil, ErrInvalid
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	orientation := 1
+	if contentType == "image/jpeg" {
+		orientation = Orientation(data)
+	}
+
+	decodeSlots <- struct{}{}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		<-decodeSlots
+		return nil, ErrInvalid
+	}
+	squares := resizeSquare(src, sizes)
+	<-decodeSlots
+
+	thumbnails := make([]Thumbnail, 0, len(sizes))
+	for i, size := range sizes {
+		img := orient(squares[i], orientation)
+		var buf bytes.Buffer
+		thumbnail := Thumbnail{Size: size}
+		if img.Opaque() {
+			thumbnail.ContentType, thumbnail.Ext = "image/jpeg", ".jpg"
+			err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+		} else {
+			thumbnail.ContentType, thumbnail.Ext = "image/png", ".png"
+			err = png.Encode(&buf, img)
+		}
+		if err != nil {
+			return nil, err
+		}
+		thumbnail.Data = buf.Bytes()
+		thumbnails = append(thumbnails, thumbnail)
+	}
+	return thumbnails, nil
+}
+
+func Orientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for pos := 2; pos+4 <= len(data) && data[pos] == 0xFF; {
+		marker := data[pos+1]
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if marker == 0xDA || length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	n := src.Bounds().Dx()
+	dst := image.NewRGBA(src.Bounds())
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = n-1-x, y
+			case 3:
+				dx, dy = n-1-x, n-1-y
+			case 4:
+				dx, dy = x, n-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = n-1-y, x
+			case 7:
+				dx, dy = n-1-y, n-1-x
+			case 8:
+				dx, dy = y, n-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+type span struct {
+	start   int
+	weights []float64
+}
+
+func spans(srcSize, dstSize int) []span {
+	scale := float64(srcSize) / float64(dstSize)
+	result := make([]span, dstSize)
+	for d := range result {
+		lo, hi := float64(d)*scale, float64(d+1)*scale
+		first := int(math.Floor(lo))
+		last := min(int(math.Ceil(hi)), srcSize)
+		weights := make([]float64, 0, last-first)
+		for s := first; s < last; s++ {
+			weights = append(weights, math.Min(hi, float64(s+1))-math.Max(lo, float64(s)))
+		}
+		result[d] = span{start: first, weights: weights}
+	}
+	return result
+}
+
+type contribution struct {
+	row    int
+	weight float64
+}
+
+type accumulator struct {
+	size    int
+	columns []span
+	rows    [][]contribution
+	sums    []float64
+	totals  []float64
+}
+
+func resizeSquare(src image.Image, sizes []int) []*image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	offset := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	accumulators := make([]accumulator, len(sizes))
+	for i, size := range sizes {
+		acc := accumulator{
+			size:    size,
+			columns: spans(side, size),
+			rows:    make([][]contribution, side),
+			sums:    make([]float64, size*size*4),
+			totals:  make([]float64, size*size),
+		}
+		for d, row := range spans(side, size) {
+			for j, weight := range row.weights {
+				acc.rows[row.start+j] = append(acc.rows[row.start+j], contribution{row: d, weight: weight})
+			}
+		}
+		accumulators[i] = acc
+	}
+
+	line := image.NewRGBA(image.Rect(0, 0, side, 1))
+	for y := 0; y < side; y++ {
+		draw.Draw(line, line.Bounds(), src, offset.Add(image.Pt(0, y)), draw.Src)
+		for _, acc := range accumulators {
+			for _, c := range acc.rows[y] {
+				for dx, column := range acc.columns {
+					o := (c.row*acc.size + dx) * 4
+					for i, wx := range column.weights {
+						w := wx * c.weight
+						p := line.Pix[(column.start+i)*4 : (column.start+i)*4+4]
+						acc.sums[o] += float64(p[0]) * w
+						acc.sums[o+1] += float64(p[1]) * w
+						acc.sums[o+2] += float64(p[2]) * w
+						acc.sums[o+3] += float64(p[3]) * w
+						acc.totals[o/4] += w
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]*image.RGBA, len(accumulators))
+	for i, acc := range accumulators {
+		dst := image.NewRGBA(image.Rect(0, 0, acc.size, acc.size))
+		for o, total := range acc.totals {
+			for c := 0; c < 4; c++ {
+				dst.Pix[o*4+c] = uint8(math.Round(acc.sums[o*4+c] / total))
+			}
+		}
+		result[i] = dst
+	}
+	return result
+}
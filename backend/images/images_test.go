@@ -0,0 +1,238 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	exif := append([]byte("Exif\x00\x00"), []byte("GPS 51.5N 0.1W")...)
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+	return append(append([]byte{0xFF, 0xD8}, segment...), buf.Bytes()[2:]...)
+}
+
+func TestThumbnails(t *testing.T) {
+	data := encodeJPEG(t, 300, 200)
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil || !bytes.Contains(data, []byte("Exif")) {
+		t.Fatalf("test image is invalid: %v", err)
+	}
+
+	thumbnails, err := Thumbnails(data, Sizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thumbnails) != len(Sizes) {
+		t.Fatalf("expected %d thumbnails, got %d", len(Sizes), len(thumbnails))
+	}
+	for i, thumbnail := range thumbnails {
+		if thumbnail.ContentType != "image/jpeg" || thumbnail.Ext != ".jpg" {
+			t.Errorf("opaque images should be stored as JPEG, got %s", thumbnail.ContentType)
+		}
+		if bytes.Contains(thumbnail.Data, []byte("Exif")) || bytes.Contains(thumbnail.Data, []byte("GPS")) {
+			t.Errorf("thumbnail %d still carries EXIF metadata", thumbnail.Size)
+		}
+		config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail.Data))
+		if err != nil || config.Width != Sizes[i] || config.Height != Sizes[i] {
+			t.Errorf("expected a %dx%d thumbnail, got %dx%d (%v)", Sizes[i], Sizes[i], config.Width, config.Height, err)
+		}
+	}
+}
+
+func TestThumbnailsKeepTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	img.Set(5, 5, color.NRGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	thumbnails, err := Thumbnails(buf.Bytes(), []int{40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumbnails[0].ContentType != "image/png" {
+		t.Errorf("transparent images should be stored as PNG, got %s", thumbnails[0].ContentType)
+	}
+	decoded, err := png.Decode(bytes.NewReader(thumbnails[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := decoded.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("expected a transparent corner, got alpha %d", a)
+	}
+	if r, _, _, a := decoded.At(21, 21).RGBA(); r == 0 || a == 0 {
+		t.Errorf("expected the red pixel to be scaled up, got r=%d a=%d", r, a)
+	}
+}
+
+func TestThumbnailsRejectInvalidUploads(t *testing.T) {
+	if _, err := Thumbnails([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), Sizes); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported for SVG, got %v", err)
+	}
+	if _, err := Thumbnails(append([]byte{0xFF, 0xD8, 0xFF}, "truncated"...), Sizes); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected ErrInvalid for a truncated JPEG, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, MaxDimension+1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Thumbnails(buf.Bytes(), Sizes); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	buf.Reset()
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, MaxDimension, MaxPixels/MaxDimension+1))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Thumbnails(buf.Bytes(), Sizes); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge for too many pixels, got %v", err)
+	}
+}
+
+func orientedJPEG(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if x < 32 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, byte(orientation >> 8), byte(orientation), 0, 0, 0, 0, 0, 0, 0, 0}
+	exif := append([]byte("Exif\x00\x00"), tiff...)
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+	return append(append([]byte{0xFF, 0xD8}, segment...), buf.Bytes()[2:]...)
+}
+
+func TestThumbnailsApplyOrientation(t *testing.T) {
+	if got := Orientation(encodeJPEG(t, 10, 10)); got != 1 {
+		t.Errorf("EXIF without an orientation should be upright, got %d", got)
+	}
+
+	red := func(img image.Image, x, y int) bool {
+		r, _, b, _ := img.At(x, y).RGBA()
+		return r > b
+	}
+	for orientation, want := range map[uint16][4]bool{
+		1: {true, false, true, false},
+		3: {false, true, false, true},
+		6: {true, true, false, false},
+		8: {false, false, true, true},
+	} {
+		data := orientedJPEG(t, orientation)
+		if got := Orientation(data); got != int(orientation) {
+			t.Fatalf("expected orientation %d, got %d", orientation, got)
+		}
+		thumbnails, err := Thumbnails(data, []int{32})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(thumbnails[0].Data, []byte("Exif")) {
+			t.Errorf("orientation %d: thumbnail still carries EXIF metadata", orientation)
+		}
+		img, err := jpeg.Decode(bytes.NewReader(thumbnails[0].Data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := [4]bool{red(img, 4, 4), red(img, 27, 4), red(img, 4, 27), red(img, 27, 27)}
+		if got != want {
+			t.Errorf("orientation %d: red corners (top-left, top-right, bottom-left, bottom-right) are %v, want %v", orientation, got, want)
+		}
+	}
+}
+
+func TestInitials(t *testing.T) {
+	cases := map[string]string{
+		"Ada Lovelace":             "AL",
+		"  grace  brewster hopper": "GH",
+		"Émile":                    "É",
+		"(Bob) 'the builder'":      "BB",
+		"":                         "?",
+		"--- ...":                  "?",
+	}
+	for name, want := range cases {
+		if got := Initials(name); got != want {
+			t.Errorf("Initials(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGeneratedImagesAreDeterministic(t *testing.T) {
+	first, err := InitialsPNG("Émile Zola", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := InitialsPNG("Émile Zola", 64)
+	if !bytes.Equal(first, second) {
+		t.Error("initials avatars differ for the same name")
+	}
+	img, err := png.Decode(bytes.NewReader(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 64 || img.Bounds().Dy() != 64 {
+		t.Errorf("avatar is %v, want 64x64", img.Bounds())
+	}
+	background := img.At(0, 0)
+	foreground := 0
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r == 0xffff && g == 0xffff && b == 0xffff {
+				foreground++
+			}
+		}
+	}
+	if foreground == 0 {
+		t.Error("initials are not drawn")
+	}
+	other, _ := InitialsPNG("Ada Lovelace", 64)
+	otherImg, _ := png.Decode(bytes.NewReader(other))
+	if otherImg.At(0, 0) == background {
+		t.Error("different names should get different colors")
+	}
+
+	if !bytes.Equal(IdenticonSVG("team-1", 128), IdenticonSVG("team-1", 128)) {
+		t.Error("identicons differ for the same seed")
+	}
+	if bytes.Equal(IdenticonSVG("team-1", 128), IdenticonSVG("team-2", 128)) {
+		t.Error("identicons match for different seeds")
+	}
+	data, err := IdenticonPNG("team-1", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	icon, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 50; x++ {
+			if icon.At(x, y) != icon.At(99-x, y) {
+				t.Fatalf("identicon is not mirrored at (%d, %d)", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"coaching-backend/config"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+type contextKey struct{}
+
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if strings.EqualFold(value, "warning") {
+		value = "warn"
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("invalid log level %q, expected debug, info, warn or error", value)
+	}
+	return level, nil
+}
+
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "text" {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+func Setup(cfg config.Log) error {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	logger := New(os.Stdout, level, cfg.Format)
+	slog.SetDefault(logger)
+	return nil
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+func Latency(start time.Time) slog.Attr {
+	return slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000)
+}
@@ -1,31 +1,52 @@
 package main
 
 import (
+	"coaching-backend/audit"
+	"coaching-backend/blob"
+	"coaching-backend/config"
 	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/grpcapi"
 	"coaching-backend/handlers"
+	"coaching-backend/health"
+	"coaching-backend/logging"
+	"coaching-backend/metrics"
+	"coaching-backend/notifications"
+	"coaching-backend/ratelimit"
+	"coaching-backend/reminders"
+	"coaching-backend/retention"
+	"coaching-backend/routes"
+	"coaching-backend/services"
+	"coaching-backend/tracing"
+	"coaching-backend/webhooks"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"log"
+	"google.golang.org/grpc"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"time"
+	"os/signal"
+	"strconv"
+	"syscall"
 )
 
-func corsMiddleware() gin.HandlerFunc {
+func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
-		allowedOrigins := []string{
-			"http://localhost:3000",
-			"http://localhost:5173",
-			"http://localhost:8080",
-		}
 
 		if origin != "" {
-			for _, allowed := range allowedOrigins {
-				if origin == allowed {
-					c.Header("Access-Control-Allow-Origin", origin)
-					break
-				}
+			c.Header("Vary", "Origin")
+			switch handlers.MatchOrigin(allowedOrigins, origin) {
+			case origin:
+				c.Header("Access-Control-Allow-Origin", origin)
+				c.Header("Access-Control-Allow-Credentials", "true")
+			case "*":
+				c.Header("Access-Control-Allow-Origin", "*")
 			}
 		} else {
 			c.Header("Access-Control-Allow-Origin", "*")
@@ -33,7 +54,6 @@ func corsMiddleware() gin.HandlerFunc {
 
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin")
-		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
@@ -45,22 +65,6 @@ func corsMiddleware() gin.HandlerFunc {
 	}
 }
 
-func requestLoggerMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
-}
-
 func securityMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("X-Content-Type-Options", "nosniff")
@@ -71,66 +75,155 @@ func securityMiddleware() gin.HandlerFunc {
 	}
 }
 
+func newEngine(cfg config.HTTP) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func rateLimitRules(cfg config.RateLimit) ratelimit.Rules {
+	rules := ratelimit.Rules{
+		Read:   cfg.Read,
+		Write:  cfg.Write,
+		Routes: map[string]ratelimit.Limit{},
+		Exempt: []string{"/health", "/livez", "/readyz", "/metrics"},
+	}
+	for _, route := range cfg.Routes {
+		key, limit, _ := ratelimit.ParseRoute(route)
+		rules.Routes[key] = limit
+	}
+	return rules
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func main() {
-	if os.Getenv("GIN_MODE") != "debug" {
-		gin.SetMode(gin.ReleaseMode)
+	cfg, err := config.Load(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
+	gin.SetMode(cfg.GinMode)
 
-	database.Connect()
+	if err := logging.Setup(cfg.Log); err != nil {
+		fatal("Failed to configure logging", err)
+	}
+	slog.Info("Configuration loaded", "env", cfg.Env, "file", cfg.Path)
 
-	r := gin.New()
-	r.Use(requestLoggerMiddleware())
-	r.Use(gin.Recovery())
-	r.Use(corsMiddleware())
-	r.Use(securityMiddleware())
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("Failed to configure tracing", err)
+	}
+	defer shutdownTracing(context.Background())
 
-	api := r.Group("/api/v1")
-	{
-		members := api.Group("/members")
-		{
-			members.POST("", handlers.CreateTeamMember)
-			members.GET("", handlers.GetTeamMembers)
-			members.GET("/:id", handlers.GetTeamMember)
-			members.PUT("/:id", handlers.UpdateTeamMember)
-			members.DELETE("/:id", handlers.DeleteTeamMember)
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		teams := api.Group("/teams")
-		{
-			teams.POST("", handlers.CreateTeam)
-			teams.GET("", handlers.GetTeams)
-			teams.GET("/:id", handlers.GetTeam)
-			teams.PUT("/:id", handlers.UpdateTeam)
-			teams.DELETE("/:id", handlers.DeleteTeam)
-			teams.POST("/assign", handlers.AssignMemberToTeam)
-			teams.DELETE("/members/:memberID", handlers.RemoveMemberFromTeam)
-		}
+	if err := encryption.Setup(cfg.Encryption); err != nil {
+		fatal("Failed to load encryption keys", err)
+	}
+	if encryption.Enabled() {
+		slog.Info("Feedback content encryption enabled", "active_key", encryption.ActiveKeyID(), "search_index", encryption.SearchIndexEnabled())
+	}
 
-		feedbacks := api.Group("/feedbacks")
-		{
-			feedbacks.POST("", handlers.CreateFeedback)
-			feedbacks.GET("", handlers.GetFeedbacks)
-			feedbacks.GET("/:id", handlers.GetFeedback)
-			feedbacks.PUT("/:id", handlers.UpdateFeedback)
-			feedbacks.DELETE("/:id", handlers.DeleteFeedback)
-		}
+	database.Connect(cfg.Database, cfg.Env)
+	health.CheckMigrations(ctx)
+	handlers.AllowedOrigins = cfg.CORS.AllowedOrigins
+	handlers.Config = cfg
+	health.ReadinessTimeout = cfg.Health.ReadinessTimeout
+	health.PoolSaturation = cfg.Health.PoolSaturation
+	services.SigningKey = []byte(cfg.Signing.Key)
+	services.Blobs = blob.NewLocal(cfg.Media.Dir)
+	services.MediaBaseURL = cfg.Media.BaseURL
+	handlers.MaxUploadBytes = int64(cfg.Media.MaxUploadBytes)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	workers := []<-chan struct{}{reminders.StartScheduler(workerCtx, cfg.Reminders)}
+	if cfg.Retention.Enabled {
+		workers = append(workers, retention.StartScheduler(workerCtx, cfg.Retention))
+	}
+	if cfg.Database.DSNFile != "" && cfg.Database.DSNReloadInterval > 0 {
+		workers = append(workers, database.WatchDSNFile(workerCtx, cfg.Database.DSNFile, cfg.Database.DSNReloadInterval))
 	}
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "ok",
-			"timestamp": time.Now().Format(time.RFC3339),
-			"version":   "1.0.0",
-		})
-	})
+	events.Subscribe(audit.Record)
+	events.Subscribe(events.Stream.Publish)
+	events.Subscribe(metrics.HandleEvent)
+	events.Subscribe(webhooks.Enqueue)
+	events.Subscribe(handlers.BroadcastMembershipChange)
+	workers = append(workers, webhooks.StartWorker(workerCtx, cfg.Webhooks))
+
+	sender, err := notifications.NewSender(cfg.Notifications)
+	if err != nil {
+		fatal("Failed to configure notification sender", err)
+	}
+	events.Subscribe(notifications.HandleEvent)
+	workers = append(workers, notifications.Start(workerCtx, sender))
+
+	r, err := newEngine(cfg.HTTP)
+	if err != nil {
+		fatal("Failed to configure trusted proxies", err)
+	}
+	r.Use(tracing.Middleware())
+	r.Use(logging.Middleware())
+	r.Use(metrics.Middleware())
+	r.Use(logging.Recovery())
+	r.Use(corsMiddleware(cfg.CORS.AllowedOrigins))
+	r.Use(securityMiddleware())
+	var grpcOpts []grpc.ServerOption
+	if cfg.RateLimit.Enabled {
+		store, rules := ratelimit.NewMemoryStore(), rateLimitRules(cfg.RateLimit)
+		r.Use(ratelimit.Middleware(store, rules, nil))
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(ratelimit.UnaryInterceptor(store, rules, nil)),
+			grpc.ChainStreamInterceptor(ratelimit.StreamInterceptor(store, rules, nil)),
+		)
+	}
+
+	routes.Register(r)
+
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.GRPC.Port))
+	if err != nil {
+		fatal("Failed to listen for gRPC", err)
+	}
+
+	server := newHTTPServer(":"+strconv.Itoa(cfg.HTTP.Port), r, cfg.HTTP)
+	server.RegisterOnShutdown(handlers.CloseStreams)
+	grpcServer := grpcapi.NewServer(grpcOpts...)
+
+	serveErrs := make(chan error, 2)
+	go func() {
+		slog.Info("gRPC server starting", "port", cfg.GRPC.Port)
+		if err := grpcServer.Serve(listener); err != nil {
+			serveErrs <- err
+		}
+	}()
+	go func() {
+		slog.Info("Server starting", "port", cfg.HTTP.Port, "read_timeout", cfg.HTTP.ReadTimeout.String(), "write_timeout", cfg.HTTP.WriteTimeout.String(), "idle_timeout", cfg.HTTP.IdleTimeout.String())
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serveErrs <- err
+		}
+	}()
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received", "timeout", cfg.HTTP.ShutdownTimeout.String())
+	case err := <-serveErrs:
+		fatal("Server failed", err)
 	}
+	stop()
 
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	if err := drain(cfg.HTTP, server, grpcServer, stopWorkers, workers...); err != nil {
+		slog.Error("Shutdown did not complete cleanly", "error", err)
 	}
+	slog.Info("Server stopped")
 }
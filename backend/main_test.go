@@ -1,24 +1,83 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"coaching-backend/audit"
+	"coaching-backend/blob"
+	"coaching-backend/config"
 	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/grpcapi"
+	"coaching-backend/grpcapi/coachingpb"
 	"coaching-backend/handlers"
+	"coaching-backend/health"
+	"coaching-backend/logging"
+	"coaching-backend/metrics"
 	"coaching-backend/models"
+	"coaching-backend/notifications"
+	"coaching-backend/ratelimit"
+	"coaching-backend/reminders"
+	"coaching-backend/retention"
+	"coaching-backend/routes"
+	"coaching-backend/services"
+	"coaching-backend/tracing"
+	"coaching-backend/webhooks"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+var subscribeOnce sync.Once
+
 func setupTestAPI() (*gin.Engine, *gorm.DB) {
 	gin.SetMode(gin.TestMode)
 
+	subscribeOnce.Do(func() {
+		events.Subscribe(audit.Record)
+		events.Subscribe(events.Stream.Publish)
+		events.Subscribe(metrics.HandleEvent)
+		events.Subscribe(webhooks.Enqueue)
+		events.Subscribe(handlers.BroadcastMembershipChange)
+	})
+
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
 		panic("Failed to connect to test database")
@@ -28,9 +87,18 @@ func setupTestAPI() (*gin.Engine, *gorm.DB) {
 		panic("Failed to migrate test database")
 	}
 
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		panic("Failed to register tracing plugin")
+	}
+
 	database.DB = db
+	webhooks.InvalidateSubscriptions()
+	health.CheckMigrations(context.Background())
 
 	r := gin.New()
+	r.Use(tracing.Middleware())
+	r.Use(logging.Middleware())
+	r.Use(metrics.Middleware())
 
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -43,45 +111,19 @@ func setupTestAPI() (*gin.Engine, *gorm.DB) {
 		c.Next()
 	})
 
-	api := r.Group("/api/v1")
-	{
-		members := api.Group("/members")
-		{
-			members.POST("", handlers.CreateTeamMember)
-			members.GET("", handlers.GetTeamMembers)
-			members.GET("/:id", handlers.GetTeamMember)
-			members.PUT("/:id", handlers.UpdateTeamMember)
-			members.DELETE("/:id", handlers.DeleteTeamMember)
-		}
-
-		teams := api.Group("/teams")
-		{
-			teams.POST("", handlers.CreateTeam)
-			teams.GET("", handlers.GetTeams)
-			teams.GET("/:id", handlers.GetTeam)
-			teams.PUT("/:id", handlers.UpdateTeam)
-			teams.DELETE("/:id", handlers.DeleteTeam)
-			teams.POST("/assign", handlers.AssignMemberToTeam)
-			teams.DELETE("/members/:memberID", handlers.RemoveMemberFromTeam)
-		}
-
-		feedbacks := api.Group("/feedbacks")
-		{
-			feedbacks.POST("", handlers.CreateFeedback)
-			feedbacks.GET("", handlers.GetFeedbacks)
-			feedbacks.GET("/:id", handlers.GetFeedback)
-			feedbacks.PUT("/:id", handlers.UpdateFeedback)
-			feedbacks.DELETE("/:id", handlers.DeleteFeedback)
-		}
-	}
-
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	routes.Register(r)
 
 	return r, db
 }
 
+func sendJSON(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
 func TestHealthEndpoint(t *testing.T) {
 	router, _ := setupTestAPI()
 
@@ -110,6 +152,34 @@ func TestCORSHeaders(t *testing.T) {
 	assert.Equal(t, "Content-Type, Authorization", w.Header().Get("Access-Control-Allow-Headers"))
 }
 
+func TestCORSMiddlewareOrigins(t *testing.T) {
+	preflight := func(allowed []string, origin string) http.Header {
+		router := gin.New()
+		router.Use(corsMiddleware(allowed))
+		req := httptest.NewRequest("OPTIONS", "/api/v1/members", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	header := preflight([]string{"https://app.example.com"}, "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+
+	header = preflight([]string{"https://app.example.com"}, "https://evil.example.com")
+	assert.Empty(t, header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, header.Get("Access-Control-Allow-Credentials"))
+
+	header = preflight([]string{"*"}, "https://evil.example.com")
+	assert.Equal(t, "*", header.Get("Access-Control-Allow-Origin"), "a wildcard is sent literally")
+	assert.Empty(t, header.Get("Access-Control-Allow-Credentials"), "credentials are never allowed for any origin")
+
+	header = preflight([]string{"*", "https://app.example.com"}, "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+}
+
 func TestCompleteWorkflow(t *testing.T) {
 	router, _ := setupTestAPI()
 
@@ -185,3 +255,1902 @@ func TestCompleteWorkflow(t *testing.T) {
 	assert.Equal(t, "Great work on the project!", createdFeedback.Content)
 	assert.Equal(t, "John Doe", createdFeedback.TargetName)
 }
+
+func TestFeedbackGapReminders(t *testing.T) {
+	router, db := setupTestAPI()
+
+	longAgo := time.Now().Add(-200 * 24 * time.Hour)
+	db.Create(&models.TeamMember{ID: "member-quiet", Name: "Quiet Member", Email: "quiet@example.com", CreatedAt: longAgo})
+	db.Create(&models.TeamMember{ID: "member-new", Name: "New Member", Email: "new@example.com"})
+	db.Create(&models.Team{ID: "team-quiet", Name: "Quiet Team", CreatedAt: longAgo})
+	db.Create(&models.Feedback{ID: "feedback-old", Content: "Old feedback", TargetType: "member", TargetID: "member-quiet", CreatedAt: longAgo})
+
+	created, err := reminders.Scan(90*24*time.Hour, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+
+	created, err = reminders.Scan(90*24*time.Hour, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+
+	req := httptest.NewRequest("GET", "/api/v1/reminders?target_type=member", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var open []models.Reminder
+	err = json.Unmarshal(w.Body.Bytes(), &open)
+	assert.NoError(t, err)
+	assert.Len(t, open, 1)
+	assert.Equal(t, "member-quiet", open[0].TargetID)
+	if assert.NotNil(t, open[0].LastFeedbackAt) {
+		assert.WithinDuration(t, longAgo, *open[0].LastFeedbackAt, time.Second)
+	}
+
+	key := "member:member-quiet"
+	duplicate := models.Reminder{ID: "duplicate", TargetType: "member", TargetID: "member-quiet", Status: reminders.StatusOpen, OpenKey: &key}
+	assert.Error(t, db.Create(&duplicate).Error, "only one open reminder per target, even across replicas")
+
+	db.Create(&models.Feedback{ID: "feedback-new", Content: "Fresh feedback", TargetType: "member", TargetID: "member-quiet"})
+
+	_, err = reminders.Scan(90*24*time.Hour, time.Now())
+	assert.NoError(t, err)
+
+	req = httptest.NewRequest("GET", "/api/v1/reminders", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	open = nil
+	err = json.Unmarshal(w.Body.Bytes(), &open)
+	assert.NoError(t, err)
+	assert.Len(t, open, 1)
+	assert.Equal(t, "team-quiet", open[0].TargetID)
+
+	db.Delete(&models.Team{}, "id = ?", "team-quiet")
+	_, err = reminders.Scan(90*24*time.Hour, time.Now())
+	assert.NoError(t, err)
+	var stale models.Reminder
+	assert.NoError(t, db.First(&stale, "target_id = ?", "team-quiet").Error)
+	assert.Equal(t, reminders.StatusResolved, stale.Status, "reminders for deleted targets are resolved")
+	assert.NotNil(t, stale.ResolvedAt)
+	assert.Nil(t, stale.OpenKey)
+
+	db.Create(&models.Team{ID: "team-quiet", Name: "Quiet Team", CreatedAt: longAgo})
+	created, err = reminders.Scan(90*24*time.Hour, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created, "resolved reminders do not block new ones")
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	router, db := setupTestAPI()
+	webhooks.AllowedNetworks = config.Webhooks{AllowedNetworks: []string{"127.0.0.1"}}.ParsedAllowedNetworks()
+	defer func() { webhooks.AllowedNetworks = nil }()
+
+	var received []*http.Request
+	var receivedBodies [][]byte
+	failing := true
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		receivedBodies = append(receivedBodies, body)
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	subscription := models.WebhookSubscription{
+		URL:    receiver.URL,
+		Secret: "test-secret",
+		Events: []string{events.FeedbackCreated},
+	}
+	body, _ := json.Marshal(subscription)
+	req := httptest.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.WebhookSubscription
+	err := json.Unmarshal(w.Body.Bytes(), &created)
+	assert.NoError(t, err)
+
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Alice", Email: "alice@example.com"})
+	db.Create(&models.Team{ID: "team-1", Name: "Platform"})
+
+	body, _ = json.Marshal(handlers.AssignRequest{MemberID: "member-1", TeamID: "team-1"})
+	req = httptest.NewRequest("POST", "/api/v1/teams/assign", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	kms, err := encryption.ParseKeyring([]byte("k1 " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'a'}, 32))))
+	assert.NoError(t, err)
+	encryption.Configure(kms, false)
+	defer encryption.Configure(nil, false)
+
+	body, _ = json.Marshal(models.Feedback{Content: "Great pairing session", TargetType: "member", TargetID: "member-1"})
+	req = httptest.NewRequest("POST", "/api/v1/feedbacks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var payload string
+	db.Raw("SELECT payload FROM webhook_deliveries").Scan(&payload)
+	assert.True(t, encryption.IsEncrypted(payload), "payloads are encrypted at rest")
+	assert.NotContains(t, payload, "Alice")
+
+	now := time.Now()
+	processed, err := webhooks.ProcessPending(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Len(t, received, 1)
+
+	var delivery models.WebhookDelivery
+	db.First(&delivery, "subscription_id = ?", created.ID)
+	assert.Equal(t, webhooks.StatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+	assert.True(t, delivery.NextAttemptAt.After(now))
+
+	failing = false
+	processed, err = webhooks.ProcessPending(delivery.NextAttemptAt)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Len(t, received, 2)
+
+	last := received[1]
+	assert.Equal(t, events.FeedbackCreated, last.Header.Get(webhooks.EventHeader))
+	assert.True(t, webhooks.Verify("test-secret", last.Header.Get(webhooks.TimestampHeader), receivedBodies[1], last.Header.Get(webhooks.SignatureHeader)))
+
+	var event events.Event
+	err = json.Unmarshal(receivedBodies[1], &event)
+	assert.NoError(t, err)
+	assert.Equal(t, events.FeedbackCreated, event.Type)
+
+	req = httptest.NewRequest("GET", "/api/v1/webhooks/"+created.ID+"/deliveries", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var deliveries []models.WebhookDelivery
+	err = json.Unmarshal(w.Body.Bytes(), &deliveries)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, webhooks.StatusSucceeded, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Contains(t, deliveries[0].Payload, "Great pairing session")
+
+	db.Create(&models.WebhookSubscription{ID: "direct", URL: receiver.URL, Events: []string{"*"}})
+	body, _ = json.Marshal(map[string]interface{}{"url": receiver.URL, "events": []string{events.FeedbackCreated}, "active": false})
+	req = httptest.NewRequest("PUT", "/api/v1/webhooks/"+created.ID, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	body, _ = json.Marshal(models.Feedback{Content: "Another pairing session", TargetType: "member", TargetID: "member-1"})
+	req = httptest.NewRequest("POST", "/api/v1/feedbacks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	var queued int64
+	db.Model(&models.WebhookDelivery{}).Count(&queued)
+	assert.Equal(t, int64(2), queued, "API changes refresh the cached subscriptions and inactive ones get nothing")
+	db.Delete(&models.WebhookSubscription{}, "id = ?", "direct")
+	db.Delete(&models.WebhookDelivery{}, "subscription_id = ?", "direct")
+
+	rotated, err := encryption.ParseKeyring([]byte("k1 " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'a'}, 32)) + "\nk2 " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'b'}, 32))))
+	assert.NoError(t, err)
+	encryption.Configure(rotated, false)
+	result, serviceErr := services.ReencryptWebhookDeliveries(context.Background(), 10, false)
+	assert.Nil(t, serviceErr)
+	assert.Equal(t, services.ReencryptResult{Scanned: 1, Reencrypted: 1}, result)
+	db.Raw("SELECT payload FROM webhook_deliveries").Scan(&payload)
+	assert.Equal(t, "k2", encryption.KeyID(payload))
+}
+
+func TestWebhookPrivateTargets(t *testing.T) {
+	router, db := setupTestAPI()
+
+	var received int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+	}))
+	defer receiver.Close()
+
+	for _, target := range []string{"http://169.254.169.254/latest/meta-data", "http://10.0.0.5/hook", "http://[::1]/hook", receiver.URL} {
+		body, _ := json.Marshal(map[string]interface{}{"url": target, "events": []string{"*"}})
+		req := httptest.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	port := receiver.URL[strings.LastIndex(receiver.URL, ":"):]
+	body, _ := json.Marshal(map[string]interface{}{"url": "http://localhost" + port + "/hook", "events": []string{"*"}})
+	req := httptest.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code, "host names are checked when they are dialled")
+
+	db.Create(&models.Team{ID: "team-1", Name: "Platform"})
+	events.Emit(events.TeamUpdated, models.Team{ID: "team-1", Name: "Platform"})
+	processed, err := webhooks.ProcessPending(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Zero(t, atomic.LoadInt32(&received), "loopback receivers are refused when dialling")
+	var delivery models.WebhookDelivery
+	assert.NoError(t, db.First(&delivery).Error)
+	assert.Zero(t, delivery.LastStatusCode)
+	assert.Contains(t, delivery.LastError, "is not a public address")
+
+	webhooks.AllowedNetworks = config.Webhooks{AllowedNetworks: []string{"127.0.0.0/8", "::1"}}.ParsedAllowedNetworks()
+	defer func() { webhooks.AllowedNetworks = nil }()
+	processed, err = webhooks.ProcessPending(delivery.NextAttemptAt)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&received), "allowed internal networks are delivered to")
+}
+
+func TestFeedbackNotifications(t *testing.T) {
+	router, db := setupTestAPI()
+
+	teamID := "team-1"
+	db.Create(&models.Team{ID: teamID, Name: "Platform"})
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Alice", Email: "alice@example.com", TeamID: &teamID})
+	db.Create(&models.TeamMember{ID: "member-2", Name: "Bob", Email: "bob@example.com", TeamID: &teamID})
+
+	body, _ := json.Marshal(models.NotificationPreference{TeamFeedbackOptOut: true})
+	req := httptest.NewRequest("PUT", "/api/v1/members/member-2/notifications", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	messages, err := notifications.BuildMessages(context.Background(), models.Feedback{
+		Content:    "Smooth release this sprint",
+		TargetType: "team",
+		TargetID:   teamID,
+		TargetName: "Platform",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "alice@example.com", messages[0].To)
+	assert.Equal(t, "New feedback for Platform", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "Smooth release this sprint")
+
+	messages, err = notifications.BuildMessages(context.Background(), models.Feedback{
+		Content:    "Thanks for the code review",
+		TargetType: "member",
+		TargetID:   "member-2",
+		TargetName: "Bob",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "bob@example.com", messages[0].To)
+	assert.Contains(t, messages[0].Body, "Hi Bob,")
+
+	var out bytes.Buffer
+	sender := notifications.NewWriterSender(&out)
+	assert.NoError(t, sender.Send(messages[0]))
+	assert.Contains(t, out.String(), "To: bob@example.com")
+
+	db.Create(&models.TeamMember{ID: "member-3", Name: "Carol", Email: "carol@example.com", TeamID: &teamID})
+	var queries int32
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("count_queries", func(*gorm.DB) {
+		atomic.AddInt32(&queries, 1)
+	}))
+	notifications.HandleEvent(events.Event{Type: events.FeedbackCreated, Data: models.Feedback{
+		ID:         "feedback-1",
+		Content:    "Great incident review",
+		TargetType: "team",
+		TargetID:   teamID,
+		TargetName: "Platform",
+	}})
+	assert.Zero(t, atomic.LoadInt32(&queries), "emails are built by the worker, not in the request")
+
+	out.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	<-notifications.Start(ctx, sender)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&queries), "recipients and their preferences are loaded in one query each")
+	assert.Contains(t, out.String(), "To: alice@example.com")
+	assert.Contains(t, out.String(), "To: carol@example.com")
+	assert.NotContains(t, out.String(), "bob@example.com")
+}
+
+func TestEventHubResumeAfterRestart(t *testing.T) {
+	hub := events.NewHub(4)
+	hub.Publish(events.Event{Type: events.MemberCreated})
+	hub.Publish(events.Event{Type: events.TeamCreated})
+
+	sub, backlog, complete := hub.Subscribe(100, events.Filter{})
+	defer hub.Unsubscribe(sub)
+	assert.False(t, complete, "an ID from before a restart asks the client to reload")
+	assert.Len(t, backlog, 2)
+
+	sub2, backlog, complete := hub.Subscribe(2, events.Filter{})
+	defer hub.Unsubscribe(sub2)
+	assert.True(t, complete)
+	assert.Empty(t, backlog)
+}
+
+func TestEventStreamResume(t *testing.T) {
+	router, _ := setupTestAPI()
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	lastSeq := events.Stream.LastSeq()
+
+	body, _ := json.Marshal(models.TeamMember{Name: "Carol", Email: "carol@example.com"})
+	resp, err := http.Post(server.URL+"/api/v1/members", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	body, _ = json.Marshal(models.Team{Name: "Data"})
+	resp, err = http.Post(server.URL+"/api/v1/teams", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/api/v1/events?entities=member", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(lastSeq, 10))
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() map[string]string {
+		fields := map[string]string{}
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return fields
+			}
+			line = strings.TrimRight(line, "\n")
+			if line == "" {
+				return fields
+			}
+			if key, value, ok := strings.Cut(line, ":"); ok {
+				fields[key] = value
+			}
+		}
+	}
+
+	replayed := readEvent()
+	assert.Equal(t, events.MemberCreated, replayed["event"])
+	assert.Contains(t, replayed["data"], "carol@example.com")
+
+	body, _ = json.Marshal(models.TeamMember{Name: "Dave", Email: "dave@example.com"})
+	resp2, err := http.Post(server.URL+"/api/v1/members", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	resp2.Body.Close()
+
+	live := readEvent()
+	assert.Equal(t, events.MemberCreated, live["event"])
+	assert.Contains(t, live["data"], "dave@example.com")
+
+	replayedID, _ := strconv.ParseUint(replayed["id"], 10, 64)
+	liveID, _ := strconv.ParseUint(live["id"], 10, 64)
+	assert.Greater(t, liveID, replayedID)
+}
+
+func TestTeamCollaborationWebSocket(t *testing.T) {
+	router, db := setupTestAPI()
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	db.Create(&models.Team{ID: "team-a", Name: "Platform"})
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Alice", Email: "alice@example.com"})
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/teams/ws"
+	readType := func(conn *websocket.Conn, msgType string) map[string]interface{} {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("read %s: %v", msgType, err)
+			}
+			if msg["type"] == msgType {
+				return msg
+			}
+		}
+	}
+
+	dialFrom := func(allowed []string, origin string) error {
+		handlers.AllowedOrigins = allowed
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {origin}})
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+	defer func(allowed []string) { handlers.AllowedOrigins = allowed }(handlers.AllowedOrigins)
+	assert.NoError(t, dialFrom([]string{"https://app.example.com"}, "https://app.example.com"))
+	assert.Error(t, dialFrom([]string{"https://app.example.com"}, "https://evil.example.com"))
+	assert.NoError(t, dialFrom([]string{"*"}, "https://evil.example.com"), "a wildcard allows WebSocket upgrades like it allows CORS")
+
+	leadA, _, err := websocket.DefaultDialer.Dial(wsURL+"?team_id=team-a", nil)
+	assert.NoError(t, err)
+	defer leadA.Close()
+	leadB, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.NoError(t, err)
+	defer leadB.Close()
+
+	subscribed := readType(leadA, "ack")
+	assert.Equal(t, "team-a", subscribed["team_id"])
+
+	assert.NoError(t, leadB.WriteJSON(map[string]interface{}{"type": "subscribe", "request_id": "1", "team_id": "team-a"}))
+	readType(leadB, "ack")
+
+	unassigned := ""
+	assert.NoError(t, leadA.WriteJSON(map[string]interface{}{
+		"type": "assign", "request_id": "2", "member_id": "member-1", "team_id": "team-a", "expected_team_id": unassigned,
+	}))
+	ack := readType(leadA, "ack")
+	assert.Equal(t, "2", ack["request_id"])
+
+	change := readType(leadB, "membership")
+	assert.Equal(t, events.MemberAssigned, change["event"])
+	assert.Equal(t, "team-a", change["team_id"])
+
+	assert.NoError(t, leadB.WriteJSON(map[string]interface{}{
+		"type": "remove", "request_id": "3", "member_id": "member-1", "expected_team_id": unassigned,
+	}))
+	conflict := readType(leadB, "error")
+	assert.Equal(t, "3", conflict["request_id"])
+	assert.Equal(t, float64(http.StatusConflict), conflict["status"])
+
+	var member models.TeamMember
+	db.First(&member, "id = ?", "member-1")
+	assert.Equal(t, "team-a", *member.TeamID)
+}
+
+func TestGraphQLNestedQueryIsBatched(t *testing.T) {
+	router, db := setupTestAPI()
+
+	for _, teamID := range []string{"team-a", "team-b", "team-c"} {
+		id := teamID
+		db.Create(&models.Team{ID: id, Name: "Team " + id})
+		for _, suffix := range []string{"1", "2"} {
+			memberID := id + "-member-" + suffix
+			db.Create(&models.TeamMember{ID: memberID, Name: "Member " + memberID, Email: memberID + "@example.com", TeamID: &id})
+			for i := 0; i < 7; i++ {
+				db.Create(&models.Feedback{
+					ID:         memberID + "-feedback-" + strconv.Itoa(i),
+					Content:    "Feedback number " + strconv.Itoa(i),
+					TargetType: "member",
+					TargetID:   memberID,
+					CreatedAt:  time.Now().Add(time.Duration(i) * time.Minute),
+				})
+			}
+		}
+	}
+
+	queries := 0
+	db.Callback().Query().After("gorm:query").Register("test:count_queries", func(tx *gorm.DB) {
+		if !tx.DryRun {
+			queries++
+		}
+	})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"query": `{ teams { id name members { id name feedback(limit: 5) { id content } } } }`,
+	})
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result struct {
+		Data struct {
+			Teams []struct {
+				ID      string
+				Members []struct {
+					ID       string
+					Feedback []struct {
+						ID      string
+						Content string
+					}
+				}
+			}
+		}
+		Errors []map[string]interface{}
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Len(t, result.Data.Teams, 3)
+	for _, team := range result.Data.Teams {
+		assert.Len(t, team.Members, 2)
+		for _, member := range team.Members {
+			assert.Len(t, member.Feedback, 5)
+			assert.Equal(t, "Feedback number 6", member.Feedback[0].Content)
+		}
+	}
+	assert.Equal(t, 3, queries)
+}
+
+func TestGraphQLMutationsUseValidation(t *testing.T) {
+	router, db := setupTestAPI()
+
+	run := func(query string) map[string]interface{} {
+		body, _ := json.Marshal(map[string]interface{}{"query": query})
+		req := httptest.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var result map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return result
+	}
+
+	result := run(`mutation { createTeam(name: "X") { id } }`)
+	errs := result["errors"].([]interface{})
+	assert.Len(t, errs, 1)
+	first := errs[0].(map[string]interface{})
+	assert.Equal(t, "team name must be at least 2 characters", first["message"])
+	assert.Equal(t, "Validation failed", first["extensions"].(map[string]interface{})["code"])
+
+	result = run(`mutation { createTeam(name: "Platform") { id name } }`)
+	assert.Nil(t, result["errors"])
+	team := result["data"].(map[string]interface{})["createTeam"].(map[string]interface{})
+	assert.Equal(t, "Platform", team["name"])
+
+	result = run(`mutation { updateMember(id: "nope", name: "Ada", email: "ada@example.com") { id } }`)
+	first = result["errors"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "The requested team member does not exist", first["message"])
+
+	run(`mutation { createMember(name: "Ada", email: "ada@example.com") { id } }`)
+	result = run(`mutation { createMember(name: "Ada", email: "ada@example.com") { id } }`)
+	first = result["errors"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, first["message"], "UNIQUE", "driver errors are not exposed")
+
+	assert.NoError(t, db.Migrator().DropTable(&models.Feedback{}))
+	result = run(`{ feedbacks { id } }`)
+	first = result["errors"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Failed to fetch feedback", first["message"])
+	assert.Equal(t, "Database error", first["extensions"].(map[string]interface{})["code"])
+	result = run(`{ members { id feedback { id } } }`)
+	first = result["errors"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Failed to fetch feedback", first["message"])
+	assert.NotContains(t, first["message"], "no such table")
+}
+
+func TestGRPCServicesShareBusinessLogic(t *testing.T) {
+	router, _ := setupTestAPI()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpcapi.NewServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	ctx := context.Background()
+	teamsClient := coachingpb.NewTeamsServiceClient(conn)
+	membersClient := coachingpb.NewMembersServiceClient(conn)
+	feedbackClient := coachingpb.NewFeedbackServiceClient(conn)
+
+	_, err = teamsClient.CreateTeam(ctx, &coachingpb.CreateTeamRequest{Name: "X"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	team, err := teamsClient.CreateTeam(ctx, &coachingpb.CreateTeamRequest{Name: "Platform"})
+	assert.NoError(t, err)
+	member, err := membersClient.CreateMember(ctx, &coachingpb.CreateMemberRequest{Name: "Ada", Email: "ada@example.com"})
+	assert.NoError(t, err)
+
+	assigned, err := teamsClient.AssignMember(ctx, &coachingpb.AssignMemberRequest{MemberId: member.Id, TeamId: team.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, team.Id, assigned.GetTeamId())
+
+	stale := ""
+	_, err = teamsClient.AssignMember(ctx, &coachingpb.AssignMemberRequest{MemberId: member.Id, TeamId: team.Id, ExpectedTeamId: &stale})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = teamsClient.GetTeam(ctx, &coachingpb.GetTeamRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	for _, content := range []string{"First feedback", "Second feedback"} {
+		_, err := feedbackClient.CreateFeedback(ctx, &coachingpb.CreateFeedbackRequest{Content: content, TargetType: "member", TargetId: member.Id})
+		assert.NoError(t, err)
+	}
+
+	stream, err := feedbackClient.ListFeedbacks(ctx, &coachingpb.ListFeedbacksRequest{TargetType: "member", TargetId: member.Id})
+	assert.NoError(t, err)
+	var received []*coachingpb.Feedback
+	for {
+		feedback, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		received = append(received, feedback)
+	}
+	assert.Len(t, received, 2)
+	assert.Equal(t, "Ada", received[0].TargetName)
+
+	req := httptest.NewRequest("GET", "/api/v1/teams/"+team.Id, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restTeam models.Team
+	json.Unmarshal(w.Body.Bytes(), &restTeam)
+	assert.Len(t, restTeam.Members, 1)
+	assert.Equal(t, "Ada", restTeam.Members[0].Name)
+}
+
+func TestOpenAPISpecCoversAllRoutes(t *testing.T) {
+	router, _ := setupTestAPI()
+
+	req := httptest.NewRequest("GET", "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.1.0", spec.OpenAPI)
+
+	documented := 0
+	for _, route := range router.Routes() {
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+				segments[i] = "{" + segment[1:] + "}"
+			}
+		}
+		path := strings.Join(segments, "/")
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("route %s %s is missing from the OpenAPI spec", route.Method, route.Path)
+			continue
+		}
+		documented++
+	}
+	operations := 0
+	for _, item := range spec.Paths {
+		operations += len(item)
+	}
+	assert.Equal(t, operations, documented, "the spec documents routes that are not registered")
+
+	for _, name := range []string{"Team", "TeamMember", "Feedback", "AssignRequest"} {
+		assert.Contains(t, spec.Components.Schemas, name)
+	}
+	member := spec.Components.Schemas["TeamMember"]
+	assert.ElementsMatch(t, []interface{}{"name", "email"}, member["required"])
+	email := member["properties"].(map[string]interface{})["email"].(map[string]interface{})
+	assert.Equal(t, "email", email["format"])
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	router, _ := setupTestAPI()
+
+	body := `{"name": "Ada", "email": "ada.metrics@example.com"}`
+	req := httptest.NewRequest("POST", "/api/v1/members", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var member models.TeamMember
+	json.Unmarshal(w.Body.Bytes(), &member)
+
+	body = `{"content": "Thorough code reviews", "target_type": "member", "target_id": "` + member.ID + `"}`
+	req = httptest.NewRequest("POST", "/api/v1/feedbacks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/members/"+member.ID, nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/members/missing", nil))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	metricsBody := w.Body.String()
+
+	assert.Contains(t, metricsBody, `coaching_http_requests_total{method="GET",route="/api/v1/members/:id",status="200"}`)
+	assert.Contains(t, metricsBody, `coaching_http_requests_total{method="GET",route="/api/v1/members/:id",status="404"}`)
+	assert.Contains(t, metricsBody, `coaching_http_request_duration_seconds_bucket{method="POST",route="/api/v1/feedbacks",status="201",le="0.1"}`)
+	assert.Regexp(t, `coaching_feedbacks_created_total\{target_type="member"\} [1-9]`, metricsBody)
+	assert.Regexp(t, `coaching_events_total\{type="member.created"\} [1-9]`, metricsBody)
+	assert.Contains(t, metricsBody, "coaching_db_open_connections")
+	assert.Contains(t, metricsBody, "coaching_db_wait_count_total")
+}
+
+func TestTracingSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	router, _ := setupTestAPI()
+
+	req := httptest.NewRequest("POST", "/api/v1/teams", strings.NewReader(`{"name": "Platform"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req = httptest.NewRequest("GET", "/api/v1/teams", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var server sdktrace.ReadOnlySpan
+	var queries []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID().String() != traceID {
+			continue
+		}
+		if span.SpanKind() == trace.SpanKindServer {
+			server = span
+		} else {
+			queries = append(queries, span)
+		}
+	}
+
+	if assert.NotNil(t, server) {
+		assert.Equal(t, "GET /api/v1/teams", server.Name())
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+		assert.True(t, server.Parent().IsRemote())
+	}
+
+	names := []string{}
+	for _, span := range queries {
+		names = append(names, span.Name())
+	}
+	assert.ElementsMatch(t, []string{"gorm.query teams", "gorm.query team_members"}, names)
+	for _, span := range queries {
+		if span.Name() == "gorm.query teams" {
+			assert.Equal(t, server.SpanContext().SpanID(), span.Parent().SpanID())
+		}
+		if span.Name() == "gorm.query team_members" {
+			for _, attr := range span.Attributes() {
+				if attr.Key == "db.query.text" {
+					assert.Contains(t, attr.Value.AsString(), "team_members")
+				}
+			}
+		}
+	}
+}
+
+func TestRequestIDAndStructuredLogs(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&logs, slog.LevelDebug, "json"))
+	defer slog.SetDefault(previous)
+
+	router, _ := setupTestAPI()
+
+	req := httptest.NewRequest("POST", "/api/v1/teams", strings.NewReader(`{"name": "Platform"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	generated := w.Header().Get("X-Request-ID")
+	assert.Len(t, generated, 36)
+	var team models.Team
+	json.Unmarshal(w.Body.Bytes(), &team)
+
+	req = httptest.NewRequest("GET", "/api/v1/teams/"+team.ID, nil)
+	req.Header.Set("X-Request-ID", "req-12345")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-12345", w.Header().Get("X-Request-ID"))
+
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		lines = append(lines, entry)
+	}
+
+	find := func(requestID, msg string) map[string]interface{} {
+		for _, entry := range lines {
+			if entry["request_id"] == requestID && entry["msg"] == msg {
+				return entry
+			}
+		}
+		t.Fatalf("no %q log line for request %s in:\n%s", msg, requestID, logs.String())
+		return nil
+	}
+
+	created := find(generated, "Created team")
+	assert.Equal(t, "INFO", created["level"])
+	assert.Equal(t, "CreateTeam", created["handler"])
+	assert.Equal(t, team.ID, created["team_id"])
+	assert.Equal(t, "/api/v1/teams", created["route"])
+	assert.Contains(t, created, "latency_ms")
+
+	fetched := find("req-12345", "Fetched team")
+	assert.Equal(t, "DEBUG", fetched["level"])
+	assert.Equal(t, team.ID, fetched["team_id"])
+
+	completed := find("req-12345", "Request completed")
+	assert.Equal(t, "/api/v1/teams/:id", completed["route"])
+	assert.Equal(t, float64(http.StatusOK), completed["status"])
+	assert.Contains(t, completed, "latency_ms")
+}
+
+func TestLivenessAndReadinessProbes(t *testing.T) {
+	router, db := setupTestAPI()
+
+	probe := func(path string) (int, health.Report) {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	code, report := probe("/livez")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.Version(), report.Version)
+	assert.NotEqual(t, "1.0.0", report.Version)
+
+	code, report = probe("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	for _, name := range []string{"database", "migrations", "db_pool"} {
+		assert.Equal(t, health.StatusOK, report.Checks[name].Status, name)
+	}
+
+	assert.NoError(t, db.Migrator().DropTable(&models.Reminder{}))
+	assert.NoError(t, db.Migrator().DropColumn(&models.Team{}, "Logo"))
+	code, report = probe("/readyz")
+	assert.Equal(t, http.StatusOK, code, "migration state is cached after startup")
+	health.CheckMigrations(context.Background())
+	code, report = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+	assert.Equal(t, health.StatusFail, report.Checks["migrations"].Status)
+	assert.ElementsMatch(t, []string{"reminders", "teams.logo"}, report.Checks["migrations"].Pending)
+
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, sqlDB.Close())
+	code, report = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusFail, report.Checks["database"].Status)
+	assert.Equal(t, "database unavailable", report.Checks["database"].Error)
+}
+
+func TestGracefulShutdownDrainsInFlightRequests(t *testing.T) {
+	router, db := setupTestAPI()
+	t.Cleanup(func() { health.SetDraining(false) })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router.POST("/test/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		handlers.CreateFeedback(c)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := newHTTPServer(listener.Addr().String(), router, config.HTTP{ReadTimeout: time.Second, WriteTimeout: 5 * time.Second, ShutdownTimeout: 5 * time.Second})
+	go server.Serve(listener)
+
+	grpcServer := grpcapi.NewServer()
+	grpcListener := bufconn.Listen(1024 * 1024)
+	go grpcServer.Serve(grpcListener)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	defaults := config.Default()
+	workers := []<-chan struct{}{reminders.StartScheduler(workerCtx, defaults.Reminders), webhooks.StartWorker(workerCtx, defaults.Webhooks)}
+
+	team := models.Team{ID: "team-1", Name: "Platform"}
+	assert.NoError(t, db.Create(&team).Error)
+
+	type result struct {
+		status int
+		err    error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		body := `{"target_type":"team","target_id":"team-1","content":"Shipped during a deploy"}`
+		resp, err := http.Post("http://"+listener.Addr().String()+"/test/slow", "application/json", strings.NewReader(body))
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		responses <- result{status: resp.StatusCode}
+	}()
+	<-started
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- drain(config.HTTP{ShutdownTimeout: 5 * time.Second}, server, grpcServer, stopWorkers, workers...)
+	}()
+
+	assert.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+		return w.Code == http.StatusServiceUnavailable && strings.Contains(w.Body.String(), "server is shutting down")
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case err := <-drained:
+		t.Fatalf("drain returned before the in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	res := <-responses
+	assert.NoError(t, res.err)
+	assert.Equal(t, http.StatusCreated, res.status)
+	assert.NoError(t, <-drained)
+
+	var count int64
+	assert.Error(t, db.Model(&models.Feedback{}).Count(&count).Error, "the database pool should be closed")
+	for _, done := range workers {
+		select {
+		case <-done:
+		default:
+			t.Fatal("background worker still running after drain")
+		}
+	}
+}
+
+func TestAdminConfigIsRedacted(t *testing.T) {
+	router, _ := setupTestAPI()
+
+	t.Setenv("DB_DSN", "app:s3cret@tcp(db:3306)/coaching_app")
+	t.Setenv("SMTP_PASSWORD", "hunter2")
+	loaded, err := config.Load([]string{"--port", "8181"}, io.Discard)
+	assert.NoError(t, err)
+	handlers.Config = loaded
+	t.Cleanup(func() { handlers.Config = nil })
+
+	req := httptest.NewRequest("GET", "/api/v1/admin/config", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+	assert.NotContains(t, w.Body.String(), "hunter2")
+
+	var view handlers.ConfigView
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &view))
+	assert.Equal(t, float64(8181), view.Config["http"].(map[string]interface{})["port"])
+	assert.Equal(t, "app:[REDACTED]@tcp(db:3306)/coaching_app", view.Config["database"].(map[string]interface{})["dsn"])
+	assert.Equal(t, config.SourceFlag, view.Sources["http.port"])
+	assert.Equal(t, config.SourceEnv, view.Sources["database.dsn"])
+}
+
+func TestRateLimiting(t *testing.T) {
+	setupTestAPI()
+
+	cfg := config.Default().RateLimit
+	cfg.Read = ratelimit.Limit{Requests: 3, Per: time.Minute}
+	cfg.Routes = []string{"POST /api/v1/feedbacks=2/1m"}
+
+	router, err := newEngine(config.Default().HTTP)
+	assert.NoError(t, err)
+	router.Use(ratelimit.Middleware(ratelimit.NewMemoryStore(), rateLimitRules(cfg), nil))
+	routes.Register(router)
+
+	send := func(method, path, remoteAddr, forwardedFor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 2; i >= 0; i-- {
+		w := send("GET", "/api/v1/teams", "203.0.113.1:1234", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(i), w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3;w=60", w.Header().Get("RateLimit-Policy"))
+	}
+
+	w := send("GET", "/api/v1/members", "203.0.113.1:1234", "", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "20", w.Header().Get("Retry-After"))
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Too many requests", response["error"])
+	assert.Equal(t, "Rate limit of 3/1m exceeded, retry in 20s", response["message"])
+
+	assert.Equal(t, http.StatusOK, send("GET", "/api/v1/teams", "203.0.113.2:1234", "", "").Code, "other clients have their own bucket")
+	assert.Equal(t, http.StatusTooManyRequests, send("GET", "/api/v1/teams", "203.0.113.1:1234", "198.51.100.7", "").Code, "X-Forwarded-For from untrusted peers is ignored")
+
+	w = send("GET", "/health", "203.0.113.1:1234", "", "")
+	assert.Equal(t, http.StatusOK, w.Code, "probes are exempt")
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+
+	feedback := `{"target_type":"team","target_id":"missing","content":"Great work"}`
+	for i := 0; i < 2; i++ {
+		w = send("POST", "/api/v1/feedbacks", "203.0.113.1:1234", "", feedback)
+		assert.NotEqual(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("POST", "/api/v1/feedbacks", "203.0.113.1:1234", "", feedback).Code)
+
+	w = send("POST", "/api/v1/teams", "203.0.113.1:1234", "", `{"name":"Platform"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, "other writes use the default write limit")
+	assert.Equal(t, "60", w.Header().Get("RateLimit-Limit"))
+
+	proxied, err := newEngine(config.HTTP{TrustedProxies: []string{"10.0.0.0/8"}})
+	assert.NoError(t, err)
+	proxied.Use(ratelimit.Middleware(ratelimit.NewMemoryStore(), rateLimitRules(cfg), nil))
+	routes.Register(proxied)
+	router = proxied
+	for i := 0; i < 3; i++ {
+		send("GET", "/api/v1/teams", "10.0.0.5:1234", "198.51.100.7", "")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("GET", "/api/v1/teams", "10.0.0.5:1234", "198.51.100.7", "").Code)
+	assert.Equal(t, http.StatusOK, send("GET", "/api/v1/teams", "10.0.0.5:1234", "198.51.100.8", "").Code, "clients behind a trusted proxy are keyed by X-Forwarded-For")
+
+	router, err = newEngine(config.Default().HTTP)
+	assert.NoError(t, err)
+	router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set(ratelimit.UserKey, user)
+		}
+	})
+	router.Use(ratelimit.Middleware(ratelimit.NewMemoryStore(), rateLimitRules(cfg), nil))
+	routes.Register(router)
+	sendAs := func(user string) int {
+		req := httptest.NewRequest("GET", "/api/v1/teams", nil)
+		req.RemoteAddr = "203.0.113.1:1234"
+		req.Header.Set("X-Test-User", user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, sendAs("ada"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, sendAs("ada"))
+	assert.Equal(t, http.StatusOK, sendAs("grace"), "authenticated users behind one address have their own bucket")
+
+	router, err = newEngine(config.Default().HTTP)
+	assert.NoError(t, err)
+	router.Use(ratelimit.Middleware(ratelimit.NewMemoryStore(), rateLimitRules(cfg), func(c *gin.Context) string {
+		return "tenant:" + c.GetHeader("X-Test-User")
+	}))
+	routes.Register(router)
+	for i := 0; i < 3; i++ {
+		sendAs("ada")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, sendAs("ada"))
+	assert.Equal(t, http.StatusOK, sendAs("grace"), "a custom key function replaces the client key")
+}
+
+func TestGRPCRateLimiting(t *testing.T) {
+	setupTestAPI()
+
+	cfg := config.Default().RateLimit
+	cfg.Read = ratelimit.Limit{Requests: 2, Per: time.Minute}
+	cfg.Write = ratelimit.Limit{Requests: 1, Per: time.Minute}
+	cfg.Routes = []string{"GRPC /coaching.v1.MembersService/CreateMember=3/1m"}
+	store, rules := ratelimit.NewMemoryStore(), rateLimitRules(cfg)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpcapi.NewServer(
+		grpc.ChainUnaryInterceptor(ratelimit.UnaryInterceptor(store, rules, nil)),
+		grpc.ChainStreamInterceptor(ratelimit.StreamInterceptor(store, rules, nil)),
+	)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	ctx := context.Background()
+	teamsClient := coachingpb.NewTeamsServiceClient(conn)
+	membersClient := coachingpb.NewMembersServiceClient(conn)
+	feedbackClient := coachingpb.NewFeedbackServiceClient(conn)
+
+	var header metadata.MD
+	_, err = teamsClient.CreateTeam(ctx, &coachingpb.CreateTeamRequest{Name: "Platform"}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, header.Get("ratelimit-limit"))
+	assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+
+	_, err = teamsClient.CreateTeam(ctx, &coachingpb.CreateTeamRequest{Name: "Design"}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "Too many requests: Rate limit of 1/1m exceeded, retry in 60s", status.Convert(err).Message())
+	assert.Equal(t, []string{"60"}, header.Get("retry-after"))
+	_, err = teamsClient.DeleteTeam(ctx, &coachingpb.DeleteTeamRequest{Id: "missing"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "writes share one bucket")
+
+	_, err = membersClient.CreateMember(ctx, &coachingpb.CreateMemberRequest{Name: "Ada", Email: "ada@example.com"})
+	assert.NoError(t, err, "per-method limits apply to gRPC methods")
+
+	_, err = teamsClient.ListTeams(ctx, &coachingpb.ListTeamsRequest{})
+	assert.NoError(t, err)
+	stream, err := feedbackClient.ListFeedbacks(ctx, &coachingpb.ListFeedbacksRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+	_, err = teamsClient.ListTeams(ctx, &coachingpb.ListTeamsRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "unary and streaming reads share the read bucket")
+}
+
+func TestFeedbackContentEncryption(t *testing.T) {
+	router, db := setupTestAPI()
+	defer encryption.Configure(nil, false)
+
+	search := func(q string) []string {
+		w := sendJSON(router, "GET", "/api/v1/feedbacks?q="+url.QueryEscape(q), "")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var feedbacks []models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &feedbacks))
+		var contents []string
+		for _, feedback := range feedbacks {
+			contents = append(contents, feedback.Content)
+		}
+		return contents
+	}
+	stored := func(id string) string {
+		var content string
+		assert.NoError(t, db.Raw("SELECT content FROM feedbacks WHERE id = ?", id).Scan(&content).Error)
+		return content
+	}
+	key := func(id string, b byte) string {
+		return id + " " + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	}
+	keyring := func(lines ...string) encryption.KMS {
+		kms, err := encryption.ParseKeyring([]byte(strings.Join(lines, "\n")))
+		assert.NoError(t, err)
+		return kms
+	}
+
+	w := sendJSON(router, "POST", "/api/v1/teams", `{"name":"Platform"}`)
+	var team models.Team
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &team))
+
+	w = sendJSON(router, "POST", "/api/v1/feedbacks", `{"target_type":"team","target_id":"`+team.ID+`","content":"Written before encryption was enabled"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var legacy models.Feedback
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &legacy))
+	assert.Equal(t, []string{"Written before encryption was enabled"}, search("before ENCRYPTION"), "plaintext rows are searched with LIKE")
+
+	encryption.Configure(keyring(key("2025-01", 'a')), true)
+
+	w = sendJSON(router, "POST", "/api/v1/feedbacks", `{"target_type":"team","target_id":"`+team.ID+`","content":"Great launch, thorough rollout plan"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created models.Feedback
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "Great launch, thorough rollout plan", created.Content)
+	assert.True(t, strings.HasPrefix(stored(created.ID), "enc:v2:2025-01:"), stored(created.ID))
+	assert.NotContains(t, stored(created.ID), "launch")
+
+	w = sendJSON(router, "GET", "/api/v1/feedbacks/"+created.ID, "")
+	assert.Contains(t, w.Body.String(), "Great launch, thorough rollout plan")
+	w = sendJSON(router, "GET", "/api/v1/feedbacks/"+legacy.ID, "")
+	assert.Contains(t, w.Body.String(), "Written before encryption was enabled", "legacy plaintext rows are still readable")
+
+	w = sendJSON(router, "PUT", "/api/v1/feedbacks/"+created.ID, `{"target_type":"team","target_id":"`+team.ID+`","content":"Great launch, clear rollout plan"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Great launch, clear rollout plan")
+	assert.NotContains(t, stored(created.ID), "clear")
+
+	assert.Equal(t, []string{"Great launch, clear rollout plan"}, search("rollout CLEAR"))
+	assert.Empty(t, search("thorough"), "the index follows updates")
+	assert.Empty(t, search("before"), "legacy rows are indexed by the re-encryption command")
+
+	plaintext := stored(legacy.ID)
+	db.Exec("UPDATE feedbacks SET content = ? WHERE id = ?", stored(created.ID), legacy.ID)
+	w = sendJSON(router, "GET", "/api/v1/feedbacks/"+legacy.ID, "")
+	assert.NotEqual(t, http.StatusOK, w.Code, "ciphertext is bound to its row")
+	assert.NotContains(t, w.Body.String(), "rollout")
+	db.Exec("UPDATE feedbacks SET content = ? WHERE id = ?", plaintext, legacy.ID)
+
+	var graphqlResponse struct {
+		Data struct {
+			Feedbacks []struct {
+				Content string `json:"content"`
+			} `json:"feedbacks"`
+		} `json:"data"`
+	}
+	w = sendJSON(router, "POST", "/graphql", `{"query":"{ feedbacks(targetId: \"`+team.ID+`\") { content } }"}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &graphqlResponse))
+	if assert.Len(t, graphqlResponse.Data.Feedbacks, 2, w.Body.String()) {
+		assert.Equal(t, "Great launch, clear rollout plan", graphqlResponse.Data.Feedbacks[0].Content)
+	}
+
+	encryption.Configure(keyring(key("2025-01", 'a'), key("2026-01", 'b')), true)
+	assert.Equal(t, []string{"Great launch, clear rollout plan"}, search("rollout"), "rows indexed under a retired key still match")
+
+	result, serviceErr := services.ReencryptFeedbacks(context.Background(), 1, false, true)
+	assert.Nil(t, serviceErr)
+	assert.Equal(t, services.ReencryptResult{Scanned: 2, Reencrypted: 2, Reindexed: 2}, result)
+	assert.True(t, strings.HasPrefix(stored(created.ID), "enc:v2:2025-01:"), "dry runs change nothing")
+
+	result, serviceErr = services.ReencryptFeedbacks(context.Background(), 1, false, false)
+	assert.Nil(t, serviceErr)
+	assert.Equal(t, services.ReencryptResult{Scanned: 2, Reencrypted: 2, Reindexed: 2}, result)
+	assert.True(t, strings.HasPrefix(stored(created.ID), "enc:v2:2026-01:"))
+	assert.True(t, strings.HasPrefix(stored(legacy.ID), "enc:v2:2026-01:"))
+
+	result, serviceErr = services.ReencryptFeedbacks(context.Background(), 10, false, false)
+	assert.Nil(t, serviceErr)
+	assert.Equal(t, services.ReencryptResult{Scanned: 2}, result, "re-running is a no-op")
+
+	encryption.Configure(keyring(key("2026-01", 'b')), true)
+	assert.Equal(t, []string{"Written before encryption was enabled"}, search("before"))
+	w = sendJSON(router, "GET", "/api/v1/feedbacks/"+created.ID, "")
+	assert.Contains(t, w.Body.String(), "Great launch, clear rollout plan", "the retired key is no longer needed")
+
+	encryption.Configure(keyring(key("2026-01", 'b')), false)
+	w = sendJSON(router, "GET", "/api/v1/feedbacks?q=launch", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "search index is disabled")
+
+	w = sendJSON(router, "DELETE", "/api/v1/feedbacks/"+created.ID, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tokens int64
+	db.Model(&models.FeedbackSearchToken{}).Where("feedback_id = ?", created.ID).Count(&tokens)
+	assert.Zero(t, tokens)
+}
+
+func TestMemberDataExportAndErasure(t *testing.T) {
+	router, db := setupTestAPI()
+	defer func() { services.SigningKey = nil }()
+
+	decode := func(w *httptest.ResponseRecorder, v interface{}) {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), v), w.Body.String())
+	}
+
+	db.Create(&models.WebhookSubscription{ID: "hook-1", URL: "https://example.com/hook", Secret: "secret", Events: []string{"*"}})
+	var team models.Team
+	decode(sendJSON(router, "POST", "/api/v1/teams", `{"name":"Platform"}`), &team)
+	var leaver, peer models.TeamMember
+	decode(sendJSON(router, "POST", "/api/v1/members", `{"name":"Alice","email":"alice@example.com"}`), &leaver)
+	decode(sendJSON(router, "POST", "/api/v1/members", `{"name":"Bob","email":"bob@example.com"}`), &peer)
+	assert.Equal(t, http.StatusOK, sendJSON(router, "POST", "/api/v1/teams/assign", `{"member_id":"`+leaver.ID+`","team_id":"`+team.ID+`"}`).Code)
+	assert.Equal(t, http.StatusOK, sendJSON(router, "PUT", "/api/v1/members/"+leaver.ID+"/notifications", `{"email_opt_out":true}`).Code)
+
+	w := sendJSON(router, "POST", "/api/v1/feedbacks", `{"target_type":"member","target_id":"`+peer.ID+`","author_id":"`+leaver.ID+`","content":"Great pairing on the migration"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var given models.Feedback
+	decode(w, &given)
+	w = sendJSON(router, "POST", "/api/v1/feedbacks", `{"target_type":"member","target_id":"`+leaver.ID+`","author_id":"`+peer.ID+`","content":"Thanks for the thorough reviews"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = sendJSON(router, "POST", "/api/v1/feedbacks", `{"target_type":"team","target_id":"`+team.ID+`","author_id":"missing","content":"Nice sprint everyone"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code, "authors must exist")
+
+	w = sendJSON(router, "GET", "/api/v1/members/"+leaver.ID+"/export", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	var export services.MemberExport
+	decode(w, &export)
+	assert.Equal(t, "alice@example.com", export.Member.Email)
+	if assert.NotNil(t, export.Team) {
+		assert.Equal(t, team.ID, export.Team.ID)
+	}
+	if assert.Len(t, export.Memberships, 1) {
+		assert.Equal(t, events.MemberAssigned, export.Memberships[0].Action)
+		assert.Equal(t, team.ID, export.Memberships[0].Details["team_id"])
+	}
+	assert.Len(t, export.FeedbackGiven, 1)
+	if assert.Len(t, export.FeedbackReceived, 1) {
+		assert.Equal(t, "Thanks for the thorough reviews", export.FeedbackReceived[0].Content)
+	}
+	if assert.NotNil(t, export.NotificationPreference) {
+		assert.True(t, export.NotificationPreference.EmailOptOut)
+	}
+	assert.Len(t, export.AuditEntries, 4, "created, assigned and both feedbacks")
+
+	w = sendJSON(router, "POST", "/api/v1/members/"+leaver.ID+"/erase", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "receipts must be signed")
+
+	services.SigningKey = []byte(strings.Repeat("k", 32))
+	w = sendJSON(router, "POST", "/api/v1/members/"+leaver.ID+"/erase", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var receipt services.ErasureReceipt
+	decode(w, &receipt)
+	assert.Equal(t, leaver.ID, receipt.MemberID)
+	assert.Equal(t, int64(1), receipt.FeedbackAnonymized)
+	assert.Equal(t, int64(1), receipt.FeedbackRelabelled)
+	assert.Equal(t, int64(1), receipt.AuditEntriesScrubbed)
+	assert.Positive(t, receipt.WebhookPayloadsScrubbed)
+	assert.Positive(t, receipt.StreamEventsScrubbed)
+	assert.True(t, services.VerifyReceipt(services.SigningKey, receipt))
+
+	var payloads []string
+	db.Model(&models.WebhookDelivery{}).Pluck("payload", &payloads)
+	assert.NotEmpty(t, payloads)
+	for _, payload := range payloads {
+		assert.NotContains(t, payload, "alice@example.com")
+		assert.NotContains(t, payload, `"Alice"`)
+	}
+	sub, backlog, _ := events.Stream.Subscribe(1, events.Filter{})
+	events.Stream.Unsubscribe(sub)
+	for _, entry := range backlog {
+		data, _ := json.Marshal(entry.Event)
+		if !strings.Contains(string(data), leaver.ID) {
+			continue
+		}
+		assert.NotContains(t, string(data), "alice@example.com", entry.Event.Type)
+		assert.NotContains(t, string(data), `"Alice"`, entry.Event.Type)
+	}
+	tampered := receipt
+	tampered.FeedbackAnonymized = 0
+	assert.False(t, services.VerifyReceipt(services.SigningKey, tampered))
+
+	var erased models.TeamMember
+	assert.NoError(t, db.First(&erased, "id = ?", leaver.ID).Error)
+	assert.Equal(t, services.ErasedMemberName, erased.Name)
+	assert.NotContains(t, erased.Email, "alice")
+	assert.NotNil(t, erased.ErasedAt)
+	assert.Equal(t, team.ID, *erased.TeamID, "team sizes are preserved")
+
+	var feedbacks, authored, named, preferences int64
+	db.Model(&models.Feedback{}).Count(&feedbacks)
+	db.Model(&models.Feedback{}).Where("author_id = ?", leaver.ID).Count(&authored)
+	db.Model(&models.Feedback{}).Where("target_name = ?", "Alice").Count(&named)
+	db.Model(&models.NotificationPreference{}).Where("member_id = ?", leaver.ID).Count(&preferences)
+	assert.Equal(t, int64(2), feedbacks, "feedback counts are preserved")
+	assert.Zero(t, authored)
+	assert.Zero(t, named)
+	assert.Zero(t, preferences)
+
+	var feedbackEntries []models.AuditEntry
+	db.Where("entity_type = ?", "feedback").Find(&feedbackEntries)
+	assert.Len(t, feedbackEntries, 2)
+	for _, entry := range feedbackEntries {
+		assert.NotEqual(t, leaver.ID, entry.Details["author_id"], "audit entries cannot re-identify the author")
+	}
+	var givenEntry models.AuditEntry
+	assert.NoError(t, db.First(&givenEntry, "entity_type = ? AND entity_id = ?", "feedback", given.ID).Error)
+	assert.NotContains(t, givenEntry.Details, "author_id")
+	assert.Equal(t, peer.ID, givenEntry.Details["target_id"])
+
+	var entry models.AuditEntry
+	assert.NoError(t, db.First(&entry, "action = ? AND entity_id = ?", events.MemberErased, leaver.ID).Error)
+	assert.Equal(t, receipt.Signature, entry.Details["signature"])
+
+	w = sendJSON(router, "POST", "/api/v1/members/"+leaver.ID+"/erase", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, http.StatusNotFound, sendJSON(router, "POST", "/api/v1/members/missing/erase", "").Code)
+	w = sendJSON(router, "PUT", "/api/v1/members/"+leaver.ID, `{"name":"Alice","email":"alice@example.com"}`)
+	assert.Equal(t, http.StatusConflict, w.Code, "erased members cannot be re-identified")
+
+	w = sendJSON(router, "POST", "/api/v1/members", `{"name":"Carol","email":"carol@example.com","erased_at":"2020-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.TeamMember
+	decode(w, &created)
+	assert.Nil(t, created.ErasedAt, "erased_at is only set by erasure")
+	w = sendJSON(router, "PUT", "/api/v1/members/"+created.ID, `{"name":"Carol","email":"carol@example.com","erased_at":"2020-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, db.First(&created, "id = ?", created.ID).Error)
+	assert.Nil(t, created.ErasedAt)
+}
+
+func TestFeedbackRetention(t *testing.T) {
+	router, db := setupTestAPI()
+
+	now := time.Now()
+	years := func(n int) time.Time { return now.AddDate(-n, 0, -1) }
+	teamID := "team-1"
+	db.Create(&models.Team{ID: teamID, Name: "Platform"})
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Alice", Email: "alice@example.com"})
+	db.Create(&models.TeamMember{ID: "member-2", Name: "Bob", Email: "bob@example.com"})
+	held := "member-2"
+	for _, feedback := range []models.Feedback{
+		{ID: "old-member", TargetType: "member", TargetID: "member-1", CreatedAt: years(4)},
+		{ID: "recent-member", TargetType: "member", TargetID: "member-1", CreatedAt: years(2)},
+		{ID: "old-praise", TargetType: "member", TargetID: "member-1", Category: "praise", CreatedAt: years(2)},
+		{ID: "old-team", TargetType: "team", TargetID: teamID, CreatedAt: years(4)},
+		{ID: "ancient-team", TargetType: "team", TargetID: teamID, CreatedAt: years(6)},
+		{ID: "held-feedback", TargetType: "member", TargetID: "member-1", CreatedAt: years(4)},
+		{ID: "held-target", TargetType: "member", TargetID: "member-2", CreatedAt: years(4)},
+		{ID: "held-author", TargetType: "team", TargetID: teamID, AuthorID: &held, CreatedAt: years(6)},
+	} {
+		feedback.Content = "Feedback " + feedback.ID
+		assert.NoError(t, db.Create(&feedback).Error)
+	}
+
+	assert.Equal(t, http.StatusOK, sendJSON(router, "PUT", "/api/v1/feedbacks/held-feedback/legal-hold", `{"legal_hold":true}`).Code)
+	w := sendJSON(router, "PUT", "/api/v1/members/member-2/legal-hold", `{"legal_hold":true}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"legal_hold":true`)
+	assert.Equal(t, http.StatusBadRequest, sendJSON(router, "PUT", "/api/v1/members/member-2/legal-hold", `{}`).Code)
+	w = sendJSON(router, "PUT", "/api/v1/members/member-2", `{"name":"Bob","email":"bob@example.com","legal_hold":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var member models.TeamMember
+	assert.NoError(t, db.First(&member, "id = ?", "member-2").Error)
+	assert.True(t, member.LegalHold, "holds are only changed through the legal hold endpoint")
+	services.SigningKey = []byte(strings.Repeat("k", 32))
+	defer func() { services.SigningKey = nil }()
+	assert.Equal(t, http.StatusConflict, sendJSON(router, "POST", "/api/v1/members/member-2/erase", "").Code)
+	assert.Equal(t, http.StatusConflict, sendJSON(router, "DELETE", "/api/v1/members/member-2", "").Code)
+	assert.Equal(t, http.StatusConflict, sendJSON(router, "DELETE", "/api/v1/feedbacks/held-feedback", "").Code)
+	assert.Equal(t, http.StatusNotFound, sendJSON(router, "DELETE", "/api/v1/feedbacks/missing", "").Code)
+
+	w = sendJSON(router, "POST", "/api/v1/feedbacks", `{"target_type":"team","target_id":"`+teamID+`","category":"Not a category!","content":"Nice sprint everyone"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	policies := []config.RetentionPolicy{}
+	for _, value := range []string{"member=3y", "team=5y", "member/praise=1y"} {
+		policy, err := config.ParseRetentionPolicy(value)
+		assert.NoError(t, err)
+		policies = append(policies, policy)
+	}
+
+	remaining := func() []string {
+		var ids []string
+		db.Model(&models.Feedback{}).Order("id").Pluck("id", &ids)
+		return ids
+	}
+	all := remaining()
+
+	report, err := retention.Enforce(context.Background(), policies, now, 1, true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, all, remaining(), "dry runs delete nothing")
+	purged := map[string]retention.PolicyReport{}
+	for _, policy := range report.Policies {
+		purged[policy.Policy] = policy
+	}
+	assert.Equal(t, int64(1), purged["member/praise"].Purged)
+	assert.Equal(t, int64(3), purged["member"].Expired)
+	assert.Equal(t, int64(2), purged["member"].Held)
+	assert.Equal(t, int64(1), purged["member"].Purged)
+	assert.Equal(t, int64(2), purged["team"].Expired)
+	assert.Equal(t, int64(1), purged["team"].Purged)
+
+	w = sendJSON(router, "GET", "/api/v1/admin/retention", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	assert.Equal(t, all, remaining())
+
+	report, err = retention.Enforce(context.Background(), policies, now, 1, false)
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, []string{"held-author", "held-feedback", "held-target", "old-team", "recent-member"}, remaining())
+
+	var entries []models.AuditEntry
+	db.Where("action = ?", events.FeedbackPurged).Order("entity_id").Find(&entries)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "ancient-team", entries[0].EntityID)
+		assert.Equal(t, "team", entries[0].Details["policy"])
+		assert.Equal(t, "old-praise", entries[2].EntityID)
+		assert.Equal(t, "member/praise", entries[2].Details["policy"])
+	}
+
+	assert.Equal(t, http.StatusOK, sendJSON(router, "PUT", "/api/v1/feedbacks/held-feedback/legal-hold", `{"legal_hold":false}`).Code)
+	w = sendJSON(router, "POST", "/api/v1/admin/retention/run?dry_run=false", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, remaining(), "held-feedback", "lifted holds are purged on the next run")
+	assert.Equal(t, http.StatusBadRequest, sendJSON(router, "POST", "/api/v1/admin/retention/run?dry_run=maybe", "").Code)
+}
+
+func TestMemberProfiles(t *testing.T) {
+	router, db := setupTestAPI()
+
+	names := func(path string) []string {
+		w := sendJSON(router, "GET", path, "")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var members []models.TeamMember
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &members))
+		result := []string{}
+		for _, member := range members {
+			result = append(result, member.Name)
+		}
+		return result
+	}
+
+	w := sendJSON(router, "POST", "/api/v1/members", `{"name":"Ada","email":"ada@example.com","title":"Staff Engineer","level":"Senior","location":"London","time_zone":"Europe/London","start_date":"2020-01-06","skills":[{"name":"Go","proficiency":5},{"name":"SQL","proficiency":2}]}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var ada models.TeamMember
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ada))
+	assert.Equal(t, "senior", ada.Level)
+	assert.Equal(t, []models.Skill{{Name: "go", Proficiency: 5}, {Name: "sql", Proficiency: 2}}, ada.Skills)
+	assert.Equal(t, 88, ada.ProfileCompleteness)
+
+	w = sendJSON(router, "POST", "/api/v1/members", `{"name":"Bob","email":"bob@example.com","level":"junior","skills":[{"name":"go","proficiency":2},{"name":"golang","proficiency":4}]}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusCreated, sendJSON(router, "POST", "/api/v1/members", `{"name":"Cy","email":"cy@example.com"}`).Code)
+
+	assert.Equal(t, []string{"Ada", "Bob"}, names("/api/v1/members?skill=GO"))
+	assert.Equal(t, []string{"Ada"}, names("/api/v1/members?skill=go&min_proficiency=3"))
+	assert.Equal(t, []string{"Ada"}, names("/api/v1/members?skill=go&level=senior"))
+	assert.Equal(t, []string{"Bob"}, names("/api/v1/members?skill=golang"))
+	assert.Empty(t, names("/api/v1/members?skill=rust"))
+	assert.Equal(t, http.StatusBadRequest, sendJSON(router, "GET", "/api/v1/members?level=wizard", "").Code)
+	assert.Equal(t, http.StatusBadRequest, sendJSON(router, "GET", "/api/v1/members?min_proficiency=3", "").Code)
+
+	for body, message := range map[string]string{
+		`{"name":"Eve","email":"eve@example.com","level":"wizard"}`:                                                       "level must be one of",
+		`{"name":"Eve","email":"eve@example.com","time_zone":"Mars/Olympus"}`:                                             "time zone must be an IANA name",
+		`{"name":"Eve","email":"eve@example.com","start_date":"06/01/2020"}`:                                              "start date must be formatted as YYYY-MM-DD",
+		`{"name":"Eve","email":"eve@example.com","start_date":"2999-01-01"}`:                                              "start date must not be in the future",
+		`{"name":"Eve","email":"eve@example.com","skills":[{"name":"go","proficiency":6}]}`:                               "proficiency for skill",
+		`{"name":"Eve","email":"eve@example.com","skills":[{"name":"go","proficiency":1},{"name":"Go","proficiency":2}]}`: "listed more than once",
+	} {
+		w = sendJSON(router, "POST", "/api/v1/members", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.Contains(t, w.Body.String(), message)
+	}
+
+	w = sendJSON(router, "PUT", "/api/v1/members/"+ada.ID, `{"name":"Ada","email":"ada@example.com","picture":"https://example.com/ada.png","skills":[{"name":"go","proficiency":5}]}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ada))
+	assert.Empty(t, ada.Title, "PUT replaces the profile")
+	assert.Empty(t, ada.Level)
+	assert.Len(t, ada.Skills, 1)
+	assert.Equal(t, 44, ada.ProfileCompleteness)
+	assert.Empty(t, names("/api/v1/members?skill=sql"))
+	assert.Empty(t, names("/api/v1/members?level=senior"))
+
+	w = sendJSON(router, "PUT", "/api/v1/members/"+ada.ID, `{"name":"Ada","email":"ada@example.com","title":"Principal Engineer","level":"staff","location":"London","time_zone":"Europe/London","start_date":"2020-01-06","skills":[{"name":"go","proficiency":5}]}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ada))
+	assert.Equal(t, "https://example.com/ada.png", ada.Picture, "the picture is kept when none is sent")
+	assert.Equal(t, 100, ada.ProfileCompleteness)
+
+	w = sendJSON(router, "PUT", "/api/v1/members/"+ada.ID, `{"name":"Ada","email":"ada@example.com","title":"","level":"","location":"","time_zone":"","start_date":""}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stored models.TeamMember
+	assert.NoError(t, db.First(&stored, "id = ?", ada.ID).Error)
+	assert.Empty(t, stored.Title, "fields can be cleared")
+	assert.Empty(t, stored.Level)
+	assert.Empty(t, stored.Location)
+	assert.Empty(t, stored.TimeZone)
+	assert.Empty(t, stored.StartDate)
+	assert.Empty(t, stored.Skills)
+	assert.Equal(t, 33, stored.ProfileCompleteness)
+}
+
+func TestImageUploads(t *testing.T) {
+	router, db := setupTestAPI()
+	services.Blobs = blob.NewLocal(t.TempDir())
+
+	upload := func(path string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "upload")
+		part.Write(data)
+		form.Close()
+		req := httptest.NewRequest("POST", path, &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	photo := func(c color.Color, width, height int) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(img, img.Bounds(), &image.Uniform{C: c}, image.Point{}, draw.Src)
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, img, nil))
+		exif := []byte("Exif\x00\x00Canon EOS")
+		return append(append([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...), buf.Bytes()[2:]...)
+	}
+
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Alice", Email: "alice@example.com", Picture: "https://example.com/alice.png"})
+	db.Create(&models.Team{ID: "team-1", Name: "Platform"})
+
+	w := upload("/api/v1/members/member-1/avatar", photo(color.RGBA{R: 200, A: 255}, 640, 480))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var member models.TeamMember
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	assert.Regexp(t, `^/media/avatars/member-1/[0-9a-f]{16}-256\.jpg$`, member.Picture)
+	first := member.Picture
+
+	w = get(first)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	assert.NotContains(t, w.Body.String(), "Canon", "EXIF is stripped")
+	config, err := jpeg.DecodeConfig(w.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, 256, config.Width)
+	assert.Equal(t, 256, config.Height)
+	etag := get(first).Header().Get("ETag")
+	assert.Equal(t, http.StatusNotModified, get(first, "If-None-Match", etag).Code)
+	assert.Equal(t, http.StatusOK, get(strings.Replace(first, "-256.", "-64.", 1)).Code)
+
+	w = upload("/api/v1/members/member-1/avatar", photo(color.RGBA{B: 200, A: 255}, 300, 300))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	assert.NotEqual(t, first, member.Picture)
+	assert.Equal(t, http.StatusNotFound, get(first).Code, "replaced images are deleted")
+	assert.Equal(t, http.StatusNotFound, get(strings.Replace(first, "-256.", "-64.", 1)).Code)
+
+	w = upload("/api/v1/members/member-1/avatar", []byte("GIF89a not really"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = upload("/api/v1/members/member-1/avatar", []byte("<html><body>hi</body></html>"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	w = upload("/api/v1/members/missing/avatar", photo(color.White, 10, 10))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	handlers.MaxUploadBytes = 100
+	w = upload("/api/v1/members/member-1/avatar", photo(color.White, 10, 10))
+	handlers.MaxUploadBytes = 5 << 20
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = sendJSON(router, "POST", "/api/v1/members", `{"name":"Mallory","email":"mallory@example.com","picture":"`+member.Picture+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "media URLs are only set by uploads")
+	w = sendJSON(router, "PUT", "/api/v1/members/member-1", `{"name":"Alice","email":"alice@example.com","picture":"`+member.Picture+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code, "sending the current picture back is allowed")
+	w = sendJSON(router, "POST", "/api/v1/teams", `{"name":"Intruders","logo":"`+member.Picture+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	db.Create(&models.TeamMember{ID: "member-2", Name: "Mallory", Email: "mallory@example.com", Picture: member.Picture})
+	assert.Equal(t, http.StatusOK, sendJSON(router, "DELETE", "/api/v1/members/member-2", "").Code)
+	assert.Equal(t, http.StatusOK, get(member.Picture).Code, "only images under the owner's prefix are deleted")
+
+	w = upload("/api/v1/teams/team-1/logo", photo(color.Black, 50, 80))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var team models.Team
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &team))
+	assert.Regexp(t, `^/media/logos/team-1/`, team.Logo)
+	var stored models.Team
+	db.First(&stored, "id = ?", "team-1")
+	assert.Equal(t, team.Logo, stored.Logo)
+
+	req := httptest.NewRequest("DELETE", "/api/v1/teams/team-1/logo", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNotFound, get(team.Logo).Code)
+
+	avatar := member.Picture
+	req = httptest.NewRequest("DELETE", "/api/v1/members/member-1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusNotFound, get(avatar).Code, "deleting a member deletes their avatar")
+	assert.Equal(t, http.StatusNotFound, get("/media/../main.go").Code)
+}
+
+func TestGeneratedAvatars(t *testing.T) {
+	router, db := setupTestAPI()
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	db.Create(&models.Team{ID: "team-1", Name: "Platform"})
+	teamID := "team-1"
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Ada Lovelace", Email: "ada@example.com", TeamID: &teamID})
+	db.Create(&models.TeamMember{ID: "member-2", Name: "Grace Hopper", Email: "grace@example.com", Picture: "https://example.com/grace.png"})
+
+	w := get("/api/v1/members/member-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var member models.TeamMember
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	assert.Equal(t, "/avatars/members/member-1.svg", member.Picture)
+	assert.Equal(t, 2*100/9, member.ProfileCompleteness, "generated pictures do not count towards completeness")
+
+	var members []models.TeamMember
+	assert.NoError(t, json.Unmarshal(get("/api/v1/members").Body.Bytes(), &members))
+	pictures := map[string]string{}
+	for _, m := range members {
+		pictures[m.ID] = m.Picture
+	}
+	assert.Equal(t, "/avatars/members/member-1.svg", pictures["member-1"])
+	assert.Equal(t, "https://example.com/grace.png", pictures["member-2"])
+
+	var team models.Team
+	assert.NoError(t, json.Unmarshal(get("/api/v1/teams/team-1").Body.Bytes(), &team))
+	assert.Equal(t, "/avatars/teams/team-1.svg", team.Logo)
+	assert.Equal(t, "/avatars/members/member-1.svg", team.Members[0].Picture)
+	var teams []models.Team
+	assert.NoError(t, json.Unmarshal(get("/api/v1/teams").Body.Bytes(), &teams))
+	assert.Equal(t, "/avatars/teams/team-1.svg", teams[0].Logo)
+
+	w = get(member.Picture)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), ">AL</text>")
+	assert.Equal(t, w.Body.String(), get(member.Picture).Body.String(), "avatars are deterministic")
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, http.StatusNotModified, get(member.Picture, "If-None-Match", etag).Code)
+
+	w = get("/avatars/members/member-1.png?size=64")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	config, err := png.DecodeConfig(w.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, 64, config.Width)
+
+	w = get(team.Logo)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	assert.Equal(t, http.StatusOK, get("/avatars/teams/team-1.png").Code)
+
+	assert.Equal(t, http.StatusBadRequest, get("/avatars/members/member-1.gif").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/avatars/members/member-1.svg?size=4096").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/avatars/members/member-1.svg?size=big").Code)
+	assert.Equal(t, http.StatusNotFound, get("/avatars/members/missing.svg").Code)
+	assert.Equal(t, http.StatusNotFound, get("/avatars/teams/missing.svg").Code)
+
+	member.Name = "Ada King"
+	body, _ := json.Marshal(member)
+	req := httptest.NewRequest("PUT", "/api/v1/members/member-1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stored models.TeamMember
+	db.First(&stored, "id = ?", "member-1")
+	assert.Empty(t, stored.Picture, "generated URLs are not stored")
+	renamed := get("/avatars/members/member-1.svg")
+	assert.Contains(t, renamed.Body.String(), ">AK</text>")
+	assert.NotEqual(t, etag, renamed.Header().Get("ETag"))
+}
+
+func TestGoals(t *testing.T) {
+	router, db := setupTestAPI()
+
+	send := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		return sendJSON(router, method, path, string(data))
+	}
+
+	teamID := "team-1"
+	db.Create(&models.Team{ID: teamID, Name: "Platform"})
+	db.Create(&models.TeamMember{ID: "member-1", Name: "Ada Lovelace", Email: "ada@example.com", TeamID: &teamID})
+	db.Create(&models.TeamMember{ID: "member-2", Name: "Grace Hopper", Email: "grace@example.com"})
+
+	w := send("POST", "/api/v1/goals", models.Goal{OwnerType: "member", OwnerID: "member-1", Title: "  Lead a design review ", TargetDate: "2026-12-31", Progress: 20})
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var goal models.Goal
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+	assert.Equal(t, "Lead a design review", goal.Title)
+	assert.Equal(t, "Ada Lovelace", goal.OwnerName)
+	assert.Equal(t, services.GoalNotStarted, goal.Status)
+
+	w = send("POST", "/api/v1/goals", models.Goal{OwnerType: "team", OwnerID: teamID, Title: "Ship the v2 API", Status: "on_track"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var teamGoal models.Goal
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &teamGoal))
+
+	for _, invalid := range []models.Goal{
+		{OwnerType: "member", OwnerID: "member-1", Title: "Go", Status: "on_track"},
+		{OwnerType: "member", OwnerID: "member-1", Title: "Mentor", Progress: 101},
+		{OwnerType: "member", OwnerID: "member-1", Title: "Mentor", Status: "someday"},
+		{OwnerType: "member", OwnerID: "member-1", Title: "Mentor", TargetDate: "31/12/2026"},
+	} {
+		assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/goals", invalid).Code, invalid)
+	}
+	assert.Equal(t, http.StatusNotFound, send("POST", "/api/v1/goals", models.Goal{OwnerType: "team", OwnerID: "missing", Title: "Mentor"}).Code)
+
+	var goals []models.Goal
+	assert.NoError(t, json.Unmarshal(send("GET", "/api/v1/goals?owner_type=member&owner_id=member-1", nil).Body.Bytes(), &goals))
+	assert.Len(t, goals, 1)
+	assert.NoError(t, json.Unmarshal(send("GET", "/api/v1/goals?status=on_track", nil).Body.Bytes(), &goals))
+	assert.Len(t, goals, 1)
+	assert.Equal(t, teamGoal.ID, goals[0].ID)
+	assert.Equal(t, http.StatusBadRequest, send("GET", "/api/v1/goals?status=someday", nil).Code)
+
+	w = send("PUT", "/api/v1/goals/"+goal.ID, models.Goal{OwnerType: "team", OwnerID: teamID, Title: "Lead two design reviews", Status: "at_risk", Progress: 0})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+	assert.Equal(t, "member", goal.OwnerType, "owners cannot change")
+	assert.Equal(t, "at_risk", goal.Status)
+	assert.Equal(t, 0, goal.Progress)
+	assert.Empty(t, goal.TargetDate)
+	w = send("PUT", "/api/v1/goals/"+goal.ID, models.Goal{Title: "Lead two design reviews", Status: "completed", Progress: 60})
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+	assert.Equal(t, 100, goal.Progress)
+	var stored models.Goal
+	db.First(&stored, "id = ?", goal.ID)
+	assert.Equal(t, "completed", stored.Status)
+	assert.Equal(t, 100, stored.Progress)
+
+	w = send("POST", "/api/v1/feedbacks", models.Feedback{Content: "Great review prep", TargetType: "member", TargetID: "member-1", GoalID: &goal.ID})
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var linked models.Feedback
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &linked))
+	assert.Equal(t, goal.ID, *linked.GoalID)
+
+	w = send("POST", "/api/v1/feedbacks", models.Feedback{Content: "Solid API design", TargetType: "member", TargetID: "member-1", GoalID: &teamGoal.ID})
+	assert.Equal(t, http.StatusCreated, w.Code, "member feedback can count towards a team goal")
+	w = send("POST", "/api/v1/feedbacks", models.Feedback{Content: "Nice work overall", TargetType: "member", TargetID: "member-2", GoalID: &goal.ID})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "goals must belong to the feedback target")
+	missing := "missing"
+	w = send("POST", "/api/v1/feedbacks", models.Feedback{Content: "Nice work overall", TargetType: "member", TargetID: "member-1", GoalID: &missing})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = send("POST", "/api/v1/feedbacks", models.Feedback{Content: "Clear written updates", TargetType: "member", TargetID: "member-1"})
+	var later models.Feedback
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &later))
+	w = send("PUT", "/api/v1/feedbacks/"+later.ID+"/goal", map[string]string{"goal_id": goal.ID})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var feedbacks []models.Feedback
+	w = send("GET", "/api/v1/goals/"+goal.ID+"/feedbacks", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &feedbacks))
+	assert.Len(t, feedbacks, 2)
+	assert.NoError(t, json.Unmarshal(send("GET", "/api/v1/feedbacks?goal_id="+teamGoal.ID, nil).Body.Bytes(), &feedbacks))
+	assert.Len(t, feedbacks, 1)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/goals/missing/feedbacks", nil).Code)
+
+	w = send("PUT", "/api/v1/feedbacks/"+later.ID, models.Feedback{Content: "Clear written updates!", TargetType: "member", TargetID: "member-1"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	db.First(&later, "id = ?", later.ID)
+	assert.Equal(t, goal.ID, *later.GoalID, "updates without goal_id keep the link")
+	w = send("PUT", "/api/v1/feedbacks/"+later.ID+"/goal", map[string]interface{}{"goal_id": nil})
+	assert.Equal(t, http.StatusOK, w.Code)
+	db.First(&later, "id = ?", later.ID)
+	assert.Nil(t, later.GoalID)
+
+	assert.Equal(t, http.StatusOK, send("DELETE", "/api/v1/goals/"+goal.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/goals/"+goal.ID, nil).Code)
+	db.First(&linked, "id = ?", linked.ID)
+	assert.Nil(t, linked.GoalID, "deleting a goal unlinks its feedback")
+	assert.Equal(t, http.StatusNotFound, send("DELETE", "/api/v1/goals/"+goal.ID, nil).Code)
+
+	var actions []string
+	db.Model(&models.AuditEntry{}).Where("entity_type = ?", "goal").Order("created_at").Pluck("action", &actions)
+	assert.Contains(t, actions, events.GoalCreated)
+	assert.Contains(t, actions, events.GoalUpdated)
+	assert.Contains(t, actions, events.GoalDeleted)
+
+	w = send("POST", "/api/v1/goals", models.Goal{OwnerType: "member", OwnerID: "member-1", Title: "Mentor a new hire"})
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &goal))
+	assert.Equal(t, http.StatusOK, send("PUT", "/api/v1/feedbacks/"+later.ID+"/goal", map[string]string{"goal_id": goal.ID}).Code)
+	assert.Equal(t, http.StatusOK, send("DELETE", "/api/v1/members/member-1", nil).Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/goals/"+goal.ID, nil).Code, "deleting a member deletes their goals")
+	db.First(&later, "id = ?", later.ID)
+	assert.Nil(t, later.GoalID)
+
+	assert.Equal(t, http.StatusOK, send("DELETE", "/api/v1/teams/"+teamID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/goals/"+teamGoal.ID, nil).Code, "deleting a team deletes its goals")
+	var remaining int64
+	db.Model(&models.Feedback{}).Where("goal_id = ?", teamGoal.ID).Count(&remaining)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"coaching-backend/database"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	dbMaxOpen           = prometheus.NewDesc("coaching_db_max_open_connections", "Maximum number of open connections to the database.", nil, nil)
+	dbOpen              = prometheus.NewDesc("coaching_db_open_connections", "Established connections, both in use and idle.", nil, nil)
+	dbInUse             = prometheus.NewDesc("coaching_db_in_use_connections", "Connections currently in use.", nil, nil)
+	dbIdle              = prometheus.NewDesc("coaching_db_idle_connections", "Idle connections.", nil, nil)
+	dbWaitCount         = prometheus.NewDesc("coaching_db_wait_count_total", "Connections waited for because the pool was exhausted.", nil, nil)
+	dbWaitDuration      = prometheus.NewDesc("coaching_db_wait_duration_seconds_total", "Time spent waiting for a connection.", nil, nil)
+	dbMaxIdleClosed     = prometheus.NewDesc("coaching_db_max_idle_closed_total", "Connections closed because of SetMaxIdleConns.", nil, nil)
+	dbMaxIdleTimeClosed = prometheus.NewDesc("coaching_db_max_idle_time_closed_total", "Connections closed because of SetConnMaxIdleTime.", nil, nil)
+	dbMaxLifetimeClosed = prometheus.NewDesc("coaching_db_max_lifetime_closed_total", "Connections closed because of SetConnMaxLifetime.", nil, nil)
+)
+
+type dbStatsCollector struct{}
+
+func (dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbMaxOpen
+	ch <- dbOpen
+	ch <- dbInUse
+	ch <- dbIdle
+	ch <- dbWaitCount
+	ch <- dbWaitDuration
+	ch <- dbMaxIdleClosed
+	ch <- dbMaxIdleTimeClosed
+	ch <- dbMaxLifetimeClosed
+}
+
+func (dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	if database.DB == nil {
+		return
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return
+	}
+
+	stats := sqlDB.Stats()
+	ch <- prometheus.MustNewConstMetric(dbMaxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(dbOpen, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(dbInUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(dbIdle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(dbWaitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(dbWaitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(dbMaxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(dbMaxIdleTimeClosed, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed))
+	ch <- prometheus.MustNewConstMetric(dbMaxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"strconv"
+	"time"
+)
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coaching",
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "coaching",
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "coaching",
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	domainEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coaching",
+		Name:      "events_total",
+		Help:      "Domain events emitted by type.",
+	}, []string{"type"})
+
+	feedbacksCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coaching",
+		Name:      "feedbacks_created_total",
+		Help:      "Feedback created by target type.",
+	}, []string{"target_type"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		httpInFlight,
+		domainEvents,
+		feedbacksCreated,
+		dbStatsCollector{},
+	)
+	for _, eventType := range events.Types {
+		domainEvents.WithLabelValues(eventType)
+	}
+	feedbacksCreated.WithLabelValues("team")
+	feedbacksCreated.WithLabelValues("member")
+}
+
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == "/metrics" {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		httpInFlight.Inc()
+		c.Next()
+		httpInFlight.Dec()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+func Handler() gin.HandlerFunc {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+	return gin.WrapH(handler)
+}
+
+func HandleEvent(event events.Event) {
+	domainEvents.WithLabelValues(event.Type).Inc()
+
+	if event.Type == events.FeedbackCreated {
+		if feedback, ok := event.Data.(models.Feedback); ok {
+			feedbacksCreated.WithLabelValues(feedback.TargetType).Inc()
+		}
+	}
+}
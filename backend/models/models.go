@@ -1,18 +1,61 @@
 package models
 
 import (
+	"coaching-backend/encryption"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 	"time"
 )
 
+func init() {
+	schema.RegisterSerializer("encrypted", encryption.Serializer{})
+}
+
 type TeamMember struct {
-	ID        string    `json:"id" gorm:"primaryKey;size:36"`
-	Name      string    `json:"name" binding:"required"`
-	Picture   string    `json:"picture"`
-	Email     string    `json:"email" binding:"required,email" gorm:"size:255;uniqueIndex"`
-	TeamID    *string   `json:"team_id" gorm:"size:36;index"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  string     `json:"id" gorm:"primaryKey;size:36"`
+	Name                string     `json:"name" binding:"required"`
+	Picture             string     `json:"picture"`
+	Email               string     `json:"email" binding:"required,email" gorm:"size:255;uniqueIndex"`
+	TeamID              *string    `json:"team_id" gorm:"size:36;index"`
+	Title               string     `json:"title" gorm:"size:100"`
+	Level               string     `json:"level" gorm:"size:20;index"`
+	Location            string     `json:"location" gorm:"size:100"`
+	TimeZone            string     `json:"time_zone" gorm:"size:64"`
+	StartDate           string     `json:"start_date" gorm:"size:10"`
+	Skills              []Skill    `json:"skills" gorm:"serializer:json;type:text"`
+	ProfileCompleteness int        `json:"profile_completeness" gorm:"-"`
+	LegalHold           bool       `json:"legal_hold" gorm:"not null;default:false;index"`
+	ErasedAt            *time.Time `json:"erased_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type Skill struct {
+	Name        string `json:"name" binding:"required"`
+	Proficiency int    `json:"proficiency" binding:"required"`
+}
+
+func (m *TeamMember) Completeness() int {
+	filled := 0
+	for _, value := range []string{m.Name, m.Email, m.Picture, m.Title, m.Level, m.Location, m.TimeZone, m.StartDate} {
+		if value != "" {
+			filled++
+		}
+	}
+	if len(m.Skills) > 0 {
+		filled++
+	}
+	return filled * 100 / 9
+}
+
+func (m *TeamMember) AfterFind(tx *gorm.DB) error {
+	m.ProfileCompleteness = m.Completeness()
+	return nil
+}
+
+func (m *TeamMember) AfterSave(tx *gorm.DB) error {
+	m.ProfileCompleteness = m.Completeness()
+	return nil
 }
 
 type Team struct {
@@ -26,14 +69,108 @@ type Team struct {
 
 type Feedback struct {
 	ID         string    `json:"id" gorm:"primaryKey;size:36"`
-	Content    string    `json:"content" binding:"required"`
+	Content    string    `json:"content" binding:"required" gorm:"serializer:encrypted"`
 	TargetType string    `json:"target_type" binding:"required,oneof=team member" gorm:"size:10;index"`
 	TargetID   string    `json:"target_id" binding:"required" gorm:"size:36;index"`
 	TargetName string    `json:"target_name"`
+	AuthorID   *string   `json:"author_id" gorm:"size:36;index"`
+	GoalID     *string   `json:"goal_id" gorm:"size:36;index"`
+	Category   string    `json:"category" gorm:"size:50;index"`
+	LegalHold  bool      `json:"legal_hold" gorm:"not null;default:false;index"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+type Goal struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:36"`
+	OwnerType   string    `json:"owner_type" gorm:"size:10;index:idx_goal_owner"`
+	OwnerID     string    `json:"owner_id" gorm:"size:36;index:idx_goal_owner"`
+	OwnerName   string    `json:"owner_name"`
+	Title       string    `json:"title" binding:"required" gorm:"size:200"`
+	Description string    `json:"description" gorm:"type:text"`
+	TargetDate  string    `json:"target_date" gorm:"size:10"`
+	Status      string    `json:"status" gorm:"size:20;index"`
+	Progress    int       `json:"progress"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Reminder struct {
+	ID             string     `json:"id" gorm:"primaryKey;size:36"`
+	TargetType     string     `json:"target_type" gorm:"size:10;index:idx_reminder_target"`
+	TargetID       string     `json:"target_id" gorm:"size:36;index:idx_reminder_target"`
+	TargetName     string     `json:"target_name"`
+	LastFeedbackAt *time.Time `json:"last_feedback_at"`
+	Status         string     `json:"status" gorm:"size:10;index"`
+	OpenKey        *string    `json:"-" gorm:"size:50;uniqueIndex"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type WebhookSubscription struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:36"`
+	URL       string    `json:"url" binding:"required" gorm:"size:2048"`
+	Secret    string    `json:"secret,omitempty" gorm:"size:255"`
+	Events    []string  `json:"events" gorm:"serializer:json;type:text"`
+	Active    *bool     `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             string     `json:"id" gorm:"primaryKey;size:36"`
+	SubscriptionID string     `json:"subscription_id" gorm:"size:36;index"`
+	EventID        string     `json:"event_id" gorm:"size:36"`
+	EventType      string     `json:"event_type" gorm:"size:50"`
+	Payload        string     `json:"payload" gorm:"type:text;serializer:encrypted"`
+	Status         string     `json:"status" gorm:"size:10;index:idx_delivery_queue"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index:idx_delivery_queue"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error" gorm:"type:text"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type NotificationPreference struct {
+	MemberID           string    `json:"member_id" gorm:"primaryKey;size:36"`
+	EmailOptOut        bool      `json:"email_opt_out"`
+	TeamFeedbackOptOut bool      `json:"team_feedback_opt_out"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type FeedbackSearchToken struct {
+	FeedbackID string `gorm:"primaryKey;size:36"`
+	Token      string `gorm:"primaryKey;size:64;index"`
+}
+
+type AuditEntry struct {
+	ID         string            `json:"id" gorm:"primaryKey;size:36"`
+	Action     string            `json:"action" gorm:"size:50;index"`
+	EntityType string            `json:"entity_type" gorm:"size:20;index:idx_audit_entity"`
+	EntityID   string            `json:"entity_id" gorm:"size:36;index:idx_audit_entity"`
+	Details    map[string]string `json:"details,omitempty" gorm:"serializer:json;type:text"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"index"`
+}
+
+func All() []interface{} {
+	return []interface{}{
+		&TeamMember{},
+		&Team{},
+		&Feedback{},
+		&Goal{},
+		&Reminder{},
+		&WebhookSubscription{},
+		&WebhookDelivery{},
+		&NotificationPreference{},
+		&FeedbackSearchToken{},
+		&AuditEntry{},
+	}
+}
+
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(&TeamMember{}, &Team{}, &Feedback{})
+	return db.AutoMigrate(All()...)
 }
@@ -1,6 +1,11 @@
 package models
 
 import (
+	"gorm.io/gorm/schema"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -74,3 +79,55 @@ func TestFeedbackModel(t *testing.T) {
 		t.Errorf("Expected TargetType to be 'member', got %s", feedback.TargetType)
 	}
 }
+
+func TestTeamMemberCompleteness(t *testing.T) {
+	member := TeamMember{Name: "Ada", Email: "ada@example.com"}
+	if got := member.Completeness(); got != 22 {
+		t.Errorf("Expected a name and email only profile to be 22%% complete, got %d", got)
+	}
+
+	member.Picture = "https://example.com/ada.png"
+	member.Title = "Engineer"
+	member.Level = "senior"
+	member.Location = "London"
+	member.TimeZone = "Europe/London"
+	member.StartDate = "2020-01-06"
+	member.Skills = []Skill{{Name: "go", Proficiency: 4}}
+	if got := member.Completeness(); got != 100 {
+		t.Errorf("Expected a full profile to be 100%% complete, got %d", got)
+	}
+}
+
+func TestSchemaFileMatchesModels(t *testing.T) {
+	data, err := os.ReadFile("../../db/schema.sql")
+	if err != nil {
+		t.Fatalf("Failed to read schema file: %v", err)
+	}
+	tables := map[string]string{}
+	for _, match := range regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\w+) \((.*?)\n\);`).FindAllStringSubmatch(string(data), -1) {
+		tables[match[1]] = match[2]
+	}
+
+	for _, model := range All() {
+		parsed, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("Failed to parse %T: %v", model, err)
+		}
+		body, ok := tables[parsed.Table]
+		if !ok {
+			t.Errorf("Expected schema.sql to create table %s", parsed.Table)
+			continue
+		}
+		columns := map[string]bool{}
+		for _, line := range strings.Split(body, "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				columns[fields[0]] = true
+			}
+		}
+		for _, name := range parsed.DBNames {
+			if !columns[name] {
+				t.Errorf("Expected schema.sql to define column %s.%s", parsed.Table, name)
+			}
+		}
+	}
+}
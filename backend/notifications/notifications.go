@@ -0,0 +1,200 @@
+package notifications
+
+import (
+	"bytes"
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var templates = template.Must(template.New("notifications").Parse(`
+{{define "member_subject"}}New feedback for you{{end}}
+{{define "member_body"}}Hi {{.Recipient.Name}},
+
+You received new feedback:
+
+"{{.Feedback.Content}}"
+
+Open the coaching app to see all the feedback you have received.
+{{end}}
+{{define "team_subject"}}New feedback for {{.Feedback.TargetName}}{{end}}
+{{define "team_body"}}Hi {{.Recipient.Name}},
+
+Your team {{.Feedback.TargetName}} received new feedback:
+
+"{{.Feedback.Content}}"
+
+Open the coaching app to see all the feedback your team has received.
+{{end}}
+`))
+
+type templateData struct {
+	Recipient models.TeamMember
+	Feedback  models.Feedback
+}
+
+var queue = make(chan models.Feedback, 256)
+
+func NewSender(cfg config.Notifications) (Sender, error) {
+	kind := cfg.Sender
+	if kind == "" {
+		kind = "stdout"
+		if cfg.SMTP.Host != "" {
+			kind = "smtp"
+		}
+	}
+
+	switch kind {
+	case "smtp":
+		return &SMTPSender{
+			Host:     cfg.SMTP.Host,
+			Port:     strconv.Itoa(cfg.SMTP.Port),
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.From,
+		}, nil
+	case "file":
+		return NewFileSender(cfg.File)
+	default:
+		return NewWriterSender(os.Stdout), nil
+	}
+}
+
+func Start(ctx context.Context, sender Sender) <-chan struct{} {
+	slog.Info("Notification worker started", "sender", fmt.Sprintf("%T", sender))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		drainCtx := context.WithoutCancel(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				flushed := flush(drainCtx, sender)
+				slog.Info("Notification worker stopped", "flushed", flushed)
+				return
+			case feedback := <-queue:
+				notify(drainCtx, sender, feedback)
+			}
+		}
+	}()
+	return done
+}
+
+func flush(ctx context.Context, sender Sender) int {
+	flushed := 0
+	for {
+		select {
+		case feedback := <-queue:
+			flushed += notify(ctx, sender, feedback)
+		default:
+			return flushed
+		}
+	}
+}
+
+func notify(ctx context.Context, sender Sender, feedback models.Feedback) int {
+	messages, err := BuildMessages(ctx, feedback)
+	if err != nil {
+		slog.Error("Failed to build notification messages", "feedback_id", feedback.ID, "error", err)
+		return 0
+	}
+	for _, msg := range messages {
+		send(sender, msg)
+	}
+	return len(messages)
+}
+
+func send(sender Sender, msg Message) {
+	if err := sender.Send(msg); err != nil {
+		slog.Error("Failed to send notification email", "to", msg.To, "error", err)
+	}
+}
+
+func HandleEvent(event events.Event) {
+	switch event.Type {
+	case events.FeedbackCreated:
+		feedback, ok := event.Data.(models.Feedback)
+		if !ok {
+			return
+		}
+		select {
+		case queue <- feedback:
+		default:
+			slog.Warn("Notification queue full, dropping emails", "feedback_id", feedback.ID)
+		}
+	case events.MemberDeleted:
+		if ref, ok := event.Data.(events.Ref); ok {
+			database.DB.Delete(&models.NotificationPreference{}, "member_id = ?", ref.ID)
+		}
+	}
+}
+
+func BuildMessages(ctx context.Context, feedback models.Feedback) ([]Message, error) {
+	db := database.DB.WithContext(ctx)
+	var recipients []models.TeamMember
+	prefix := "member"
+
+	if feedback.TargetType == "team" {
+		prefix = "team"
+		if err := db.Where("team_id = ?", feedback.TargetID).Find(&recipients).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		if err := db.Where("id = ?", feedback.TargetID).Find(&recipients).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		ids = append(ids, recipient.ID)
+	}
+	var preferences []models.NotificationPreference
+	if err := db.Where("member_id IN ?", ids).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+	optedOut := map[string]bool{}
+	for _, preference := range preferences {
+		optedOut[preference.MemberID] = preference.EmailOptOut || (feedback.TargetType == "team" && preference.TeamFeedbackOptOut)
+	}
+
+	messages := make([]Message, 0, len(recipients))
+	for _, recipient := range recipients {
+		if recipient.ErasedAt != nil || strings.TrimSpace(recipient.Email) == "" || optedOut[recipient.ID] {
+			continue
+		}
+
+		data := templateData{Recipient: recipient, Feedback: feedback}
+		subject, err := render(prefix+"_subject", data)
+		if err != nil {
+			return nil, err
+		}
+		body, err := render(prefix+"_body", data)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, Message{To: recipient.Email, Subject: subject, Body: body})
+	}
+	return messages, nil
+}
+
+func render(name string, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"mime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMTPMessageHeaders(t *testing.T) {
+	sender := &SMTPSender{From: "coaching-app@localhost"}
+	message := string(sender.message(Message{
+		To:      "alice@example.com",
+		Subject: "New feedback for Zoë\r\nBcc: victim@example.com",
+		Body:    "Hi Zoë,\nthanks!",
+	}, time.Unix(0, 0)))
+
+	headers, body, ok := strings.Cut(message, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("message has no header separator: %q", message)
+	}
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Errorf("subject injected a header: %q", headers)
+	}
+	var subject string
+	for _, line := range strings.Split(headers, "\r\n") {
+		if value, ok := strings.CutPrefix(line, "Subject: "); ok {
+			subject = value
+		}
+	}
+	if !strings.HasPrefix(subject, "=?utf-8?q?") {
+		t.Errorf("non-ASCII subject is not encoded: %q", subject)
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(subject)
+	if err != nil || decoded != "New feedback for Zoë Bcc: victim@example.com" {
+		t.Errorf("decoded subject = %q, %v", decoded, err)
+	}
+	if body != "Hi Zoë,\r\nthanks!" {
+		t.Errorf("body = %q", body)
+	}
+}
@@ -0,0 +1,275 @@
+package openapi
+
+import (
+	"coaching-backend/handlers"
+	"coaching-backend/health"
+	"coaching-backend/models"
+	"coaching-backend/retention"
+	"coaching-backend/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Error struct {
+	Error   string `json:"error" binding:"required"`
+	Message string `json:"message" binding:"required"`
+}
+
+type Message struct {
+	Message string `json:"message" binding:"required"`
+}
+
+type Health struct {
+	Status    string `json:"status" binding:"required"`
+	Timestamp string `json:"timestamp"`
+	Version   string `json:"version"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]interface{}   `json:"data"`
+	Errors []map[string]interface{} `json:"errors"`
+}
+
+type parameter struct {
+	Name        string
+	In          string
+	Description string
+	Enum        []string
+}
+
+type operation struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Params      []parameter
+	Request     interface{}
+	Status      int
+	Response    interface{}
+	ContentType string
+	Upload      bool
+	Errors      []int
+}
+
+func query(name, description string, enum ...string) parameter {
+	return parameter{Name: name, In: "query", Description: description, Enum: enum}
+}
+
+var operations = []operation{
+	{Method: "POST", Path: "/api/v1/members", Tag: "Members", Summary: "Create a team member", Request: models.TeamMember{}, Status: http.StatusCreated, Response: models.TeamMember{}, Errors: []int{400, 409, 500}},
+	{Method: "GET", Path: "/api/v1/members", Tag: "Members", Summary: "List team members", Params: []parameter{query("skill", "Only members with this skill"), query("min_proficiency", "Only members with at least this proficiency (1-5) in skill"), query("level", "Only members at this level", services.Levels...)}, Response: []models.TeamMember{}, Errors: []int{400, 500}},
+	{Method: "GET", Path: "/api/v1/members/:id", Tag: "Members", Summary: "Get a team member", Response: models.TeamMember{}, Errors: []int{400, 404}},
+	{Method: "PUT", Path: "/api/v1/members/:id", Tag: "Members", Summary: "Update a team member", Request: models.TeamMember{}, Response: models.TeamMember{}, Errors: []int{400, 404, 409, 500}},
+	{Method: "DELETE", Path: "/api/v1/members/:id", Tag: "Members", Summary: "Delete a team member", Response: Message{}, Errors: []int{400, 404, 409, 500}},
+	{Method: "GET", Path: "/api/v1/members/:id/notifications", Tag: "Members", Summary: "Get notification preferences", Response: models.NotificationPreference{}, Errors: []int{404, 500}},
+	{Method: "PUT", Path: "/api/v1/members/:id/notifications", Tag: "Members", Summary: "Update notification preferences", Request: models.NotificationPreference{}, Response: models.NotificationPreference{}, Errors: []int{400, 404, 500}},
+	{Method: "GET", Path: "/api/v1/members/:id/export", Tag: "Members", Summary: "Export every record held about a team member as a JSON archive", Response: services.MemberExport{}, Errors: []int{404, 500}},
+	{Method: "POST", Path: "/api/v1/members/:id/erase", Tag: "Members", Summary: "Anonymize a team member and the feedback they authored, returning a signed receipt", Response: services.ErasureReceipt{}, Errors: []int{404, 409, 500, 503}},
+	{Method: "POST", Path: "/api/v1/members/:id/avatar", Tag: "Members", Summary: "Upload a JPEG, PNG or GIF avatar, stored as square thumbnails and set as the member picture", Upload: true, Response: models.TeamMember{}, Errors: []int{400, 404, 409, 413, 415, 500}},
+	{Method: "DELETE", Path: "/api/v1/members/:id/avatar", Tag: "Members", Summary: "Remove the member avatar", Response: models.TeamMember{}, Errors: []int{404, 500}},
+	{Method: "PUT", Path: "/api/v1/members/:id/legal-hold", Tag: "Members", Summary: "Place or lift a legal hold exempting a member's feedback from retention and erasure", Request: handlers.LegalHoldRequest{}, Response: models.TeamMember{}, Errors: []int{400, 404, 500}},
+
+	{Method: "POST", Path: "/api/v1/teams", Tag: "Teams", Summary: "Create a team", Request: models.Team{}, Status: http.StatusCreated, Response: models.Team{}, Errors: []int{400, 409, 500}},
+	{Method: "GET", Path: "/api/v1/teams", Tag: "Teams", Summary: "List teams with their members", Response: []models.Team{}, Errors: []int{500}},
+	{Method: "GET", Path: "/api/v1/teams/:id", Tag: "Teams", Summary: "Get a team with its members", Response: models.Team{}, Errors: []int{400, 404}},
+	{Method: "PUT", Path: "/api/v1/teams/:id", Tag: "Teams", Summary: "Update a team", Request: models.Team{}, Response: models.Team{}, Errors: []int{400, 404, 500}},
+	{Method: "DELETE", Path: "/api/v1/teams/:id", Tag: "Teams", Summary: "Delete a team", Response: Message{}, Errors: []int{400, 404, 500}},
+	{Method: "POST", Path: "/api/v1/teams/:id/logo", Tag: "Teams", Summary: "Upload a JPEG, PNG or GIF logo, stored as square thumbnails and set as the team logo", Upload: true, Response: models.Team{}, Errors: []int{400, 404, 413, 415, 500}},
+	{Method: "DELETE", Path: "/api/v1/teams/:id/logo", Tag: "Teams", Summary: "Remove the team logo", Response: models.Team{}, Errors: []int{404, 500}},
+	{Method: "POST", Path: "/api/v1/teams/assign", Tag: "Teams", Summary: "Assign a member to a team", Request: handlers.AssignRequest{}, Response: Message{}, Errors: []int{400, 404, 409, 500}},
+	{Method: "DELETE", Path: "/api/v1/teams/members/:memberID", Tag: "Teams", Summary: "Remove a member from their team", Params: []parameter{query("expected_team_id", "Team the member is expected to be in; a mismatch returns 409")}, Response: Message{}, Errors: []int{400, 404, 409, 500}},
+	{Method: "GET", Path: "/api/v1/teams/ws", Tag: "Teams", Summary: "Open the team collaboration WebSocket", Params: []parameter{query("team_id", "Team room to join on connect")}, Status: http.StatusSwitchingProtocols, Errors: []int{400}},
+
+	{Method: "POST", Path: "/api/v1/feedbacks", Tag: "Feedback", Summary: "Create feedback", Request: models.Feedback{}, Status: http.StatusCreated, Response: models.Feedback{}, Errors: []int{400, 404, 500}},
+	{Method: "GET", Path: "/api/v1/feedbacks", Tag: "Feedback", Summary: "List feedback, newest first", Params: []parameter{query("target_type", "Only feedback for this target type", "team", "member"), query("target_id", "Only feedback for this target"), query("goal_id", "Only feedback linked to this goal"), query("q", "Only feedback containing every word of this search query")}, Response: []models.Feedback{}, Errors: []int{400, 500}},
+	{Method: "GET", Path: "/api/v1/feedbacks/:id", Tag: "Feedback", Summary: "Get feedback", Response: models.Feedback{}, Errors: []int{400, 404}},
+	{Method: "PUT", Path: "/api/v1/feedbacks/:id", Tag: "Feedback", Summary: "Update feedback", Request: models.Feedback{}, Response: models.Feedback{}, Errors: []int{400, 404, 500}},
+	{Method: "DELETE", Path: "/api/v1/feedbacks/:id", Tag: "Feedback", Summary: "Delete feedback", Response: Message{}, Errors: []int{400, 404, 409, 500}},
+	{Method: "PUT", Path: "/api/v1/feedbacks/:id/legal-hold", Tag: "Feedback", Summary: "Place or lift a legal hold exempting feedback from retention", Request: handlers.LegalHoldRequest{}, Response: models.Feedback{}, Errors: []int{400, 404, 500}},
+	{Method: "PUT", Path: "/api/v1/feedbacks/:id/goal", Tag: "Feedback", Summary: "Link feedback to a goal of its target, or unlink it with a null goal_id", Request: handlers.FeedbackGoalRequest{}, Response: models.Feedback{}, Errors: []int{400, 404, 500}},
+	{Method: "POST", Path: "/api/v1/goals", Tag: "Goals", Summary: "Create a goal owned by a member or team", Request: models.Goal{}, Status: http.StatusCreated, Response: models.Goal{}, Errors: []int{400, 404, 500}},
+	{Method: "GET", Path: "/api/v1/goals", Tag: "Goals", Summary: "List goals, newest first", Params: []parameter{query("owner_type", "Only goals owned by this owner type", "team", "member"), query("owner_id", "Only goals of this owner"), query("status", "Only goals with this status", services.GoalStatuses...)}, Response: []models.Goal{}, Errors: []int{400, 500}},
+	{Method: "GET", Path: "/api/v1/goals/:id", Tag: "Goals", Summary: "Get a goal", Response: models.Goal{}, Errors: []int{404}},
+	{Method: "PUT", Path: "/api/v1/goals/:id", Tag: "Goals", Summary: "Update a goal's title, description, target date, status and progress", Request: models.Goal{}, Response: models.Goal{}, Errors: []int{400, 404, 500}},
+	{Method: "DELETE", Path: "/api/v1/goals/:id", Tag: "Goals", Summary: "Delete a goal and unlink its feedback", Response: Message{}, Errors: []int{404, 500}},
+	{Method: "GET", Path: "/api/v1/goals/:id/feedbacks", Tag: "Goals", Summary: "List feedback linked to a goal, newest first", Response: []models.Feedback{}, Errors: []int{404, 500}},
+
+	{Method: "GET", Path: "/api/v1/reminders", Tag: "Reminders", Summary: "List feedback gap reminders", Params: []parameter{query("status", "Reminder status, defaults to open", "open", "resolved", "all"), query("target_type", "Only reminders for this target type", "team", "member")}, Response: []models.Reminder{}, Errors: []int{400, 500}},
+	{Method: "GET", Path: "/api/v1/events", Tag: "Events", Summary: "Stream entity events as Server-Sent Events", Params: []parameter{query("entities", "Comma separated entities to include (team, member, feedback, goal)"), query("types", "Comma separated event types to include"), query("last_event_id", "Resume after this event ID, same as the Last-Event-ID header")}, ContentType: "text/event-stream", Errors: []int{400}},
+	{Method: "GET", Path: "/api/v1/openapi.json", Tag: "Meta", Summary: "Get this OpenAPI document", Response: map[string]interface{}{}},
+
+	{Method: "POST", Path: "/api/v1/webhooks", Tag: "Webhooks", Summary: "Create a webhook subscription", Request: models.WebhookSubscription{}, Status: http.StatusCreated, Response: models.WebhookSubscription{}, Errors: []int{400, 500}},
+	{Method: "GET", Path: "/api/v1/webhooks", Tag: "Webhooks", Summary: "List webhook subscriptions", Response: []models.WebhookSubscription{}, Errors: []int{500}},
+	{Method: "GET", Path: "/api/v1/webhooks/:id", Tag: "Webhooks", Summary: "Get a webhook subscription", Response: models.WebhookSubscription{}, Errors: []int{404}},
+	{Method: "PUT", Path: "/api/v1/webhooks/:id", Tag: "Webhooks", Summary: "Update a webhook subscription", Request: models.WebhookSubscription{}, Response: models.WebhookSubscription{}, Errors: []int{400, 404, 500}},
+	{Method: "DELETE", Path: "/api/v1/webhooks/:id", Tag: "Webhooks", Summary: "Delete a webhook subscription", Response: Message{}, Errors: []int{404, 500}},
+	{Method: "GET", Path: "/api/v1/webhooks/:id/deliveries", Tag: "Webhooks", Summary: "List deliveries for a webhook subscription", Response: []models.WebhookDelivery{}, Errors: []int{404, 500}},
+
+	{Method: "GET", Path: "/api/v1/admin/config", Tag: "Admin", Summary: "Get the effective configuration with secrets redacted and the source of each overridden key", Response: handlers.ConfigView{}},
+	{Method: "GET", Path: "/api/v1/admin/retention", Tag: "Admin", Summary: "Report what the retention policies would delete now without deleting anything", Response: retention.Report{}, Errors: []int{500}},
+	{Method: "POST", Path: "/api/v1/admin/retention/run", Tag: "Admin", Summary: "Apply the retention policies now", Params: []parameter{query("dry_run", "Report without deleting, defaults to the configured dry_run", "true", "false")}, Response: retention.Report{}, Errors: []int{400, 500}},
+
+	{Method: "POST", Path: "/graphql", Tag: "GraphQL", Summary: "Execute a GraphQL query or mutation", Request: GraphQLRequest{}, Response: GraphQLResponse{}, Errors: []int{400}},
+	{Method: "GET", Path: "/metrics", Tag: "Meta", Summary: "Prometheus metrics", ContentType: "text/plain"},
+	{Method: "GET", Path: "/media/*key", Tag: "Media", Summary: "Get an uploaded image, cacheable forever", ContentType: "image/*", Errors: []int{404, 500}},
+	{Method: "GET", Path: "/avatars/members/:file", Tag: "Media", Summary: "Get a generated initials avatar for a member, file is <member id>.svg or <member id>.png", Params: []parameter{query("size", "Width and height in pixels (16-512, default 128)")}, ContentType: "image/*", Errors: []int{400, 404, 500}},
+	{Method: "GET", Path: "/avatars/teams/:file", Tag: "Media", Summary: "Get a generated identicon logo for a team, file is <team id>.svg or <team id>.png", Params: []parameter{query("size", "Width and height in pixels (16-512, default 128)")}, ContentType: "image/*", Errors: []int{400, 404, 500}},
+	{Method: "GET", Path: "/health", Tag: "Meta", Summary: "Health check", Response: Health{}},
+	{Method: "GET", Path: "/livez", Tag: "Meta", Summary: "Liveness probe", Response: health.Report{}},
+	{Method: "GET", Path: "/readyz", Tag: "Meta", Summary: "Readiness probe with per-check results, 503 with the same body when a check fails", Response: health.Report{}},
+}
+
+func specPath(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func operationID(op operation) string {
+	id := strings.ToLower(op.Method)
+	words := strings.FieldsFunc(strings.TrimPrefix(op.Path, "/api/v1"), func(r rune) bool {
+		return r == '/' || r == '.' || r == '_'
+	})
+	for _, word := range words {
+		if strings.HasPrefix(word, ":") || strings.HasPrefix(word, "*") {
+			id += "By"
+			word = word[1:]
+		}
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
+
+func Document() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	addSchema(reflect.TypeOf(Error{}), schemas)
+
+	paths := map[string]interface{}{}
+	for _, op := range operations {
+		path, pathParams := specPath(op.Path)
+
+		var params []interface{}
+		for _, name := range pathParams {
+			params = append(params, map[string]interface{}{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, p := range op.Params {
+			schema := map[string]interface{}{"type": "string"}
+			if len(p.Enum) > 0 {
+				schema["enum"] = p.Enum
+			}
+			params = append(params, map[string]interface{}{
+				"name":        p.Name,
+				"in":          p.In,
+				"description": p.Description,
+				"schema":      schema,
+			})
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		switch {
+		case op.ContentType != "":
+			response["content"] = map[string]interface{}{op.ContentType: map[string]interface{}{}}
+		case op.Response != nil:
+			response["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(op.Response), schemas)},
+			}
+		}
+		responses := map[string]interface{}{strconv.Itoa(status): response}
+		errors := op.Errors
+		if strings.HasPrefix(op.Path, "/api/") || op.Path == "/graphql" {
+			errors = append(errors[:len(errors):len(errors)], http.StatusTooManyRequests)
+		}
+		for _, code := range errors {
+			responses[strconv.Itoa(code)] = map[string]interface{}{
+				"description": http.StatusText(code),
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": ref("Error")},
+				},
+			}
+		}
+
+		operation := map[string]interface{}{
+			"tags":        []string{op.Tag},
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+			"responses":   responses,
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.Upload {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"multipart/form-data": map[string]interface{}{"schema": map[string]interface{}{
+						"type":       "object",
+						"required":   []string{"file"},
+						"properties": map[string]interface{}{"file": map[string]interface{}{"type": "string", "format": "binary"}},
+					}},
+				},
+			}
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(op.Request), schemas)},
+				},
+			}
+		}
+
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   "Coaching API",
+			"version": "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+var (
+	documentOnce sync.Once
+	document     map[string]interface{}
+)
+
+func Serve(c *gin.Context) {
+	documentOnce.Do(func() {
+		document = Document()
+	})
+	c.JSON(http.StatusOK, document)
+}
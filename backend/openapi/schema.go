@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	nullable := false
+	if t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+
+	var schema map[string]interface{}
+	switch {
+	case t == timeType:
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		addSchema(t, schemas)
+		if nullable {
+			return map[string]interface{}{"oneOf": []interface{}{ref(t.Name()), map[string]interface{}{"type": "null"}}}
+		}
+		return ref(t.Name())
+	case t.Kind() == reflect.Slice:
+		schema = map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case t.Kind() == reflect.Map:
+		schema = map[string]interface{}{"type": "object"}
+	case t.Kind() == reflect.String:
+		schema = map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		schema = map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		schema = map[string]interface{}{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		schema = map[string]interface{}{"type": "number"}
+	default:
+		schema = map[string]interface{}{}
+	}
+
+	if nullable {
+		schema["type"] = []interface{}{schema["type"], "null"}
+	}
+	return schema
+}
+
+func addSchema(t reflect.Type, schemas map[string]interface{}) {
+	if _, ok := schemas[t.Name()]; ok {
+		return
+	}
+	properties := map[string]interface{}{}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	schemas[t.Name()] = schema
+
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaFor(field.Type, schemas)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			switch {
+			case rule == "required":
+				required = append(required, name)
+			case rule == "email":
+				property["format"] = "email"
+			case strings.HasPrefix(rule, "oneof="):
+				var values []interface{}
+				for _, v := range strings.Fields(strings.TrimPrefix(rule, "oneof=")) {
+					values = append(values, v)
+				}
+				property["enum"] = values
+			}
+		}
+		if strings.Contains(field.Tag.Get("gorm"), "primaryKey") || name == "created_at" || name == "updated_at" {
+			property["readOnly"] = true
+		}
+		properties[name] = property
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+}
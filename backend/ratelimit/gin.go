@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const UserKey = "user_id"
+
+type KeyFunc func(c *gin.Context) string
+
+type Rules struct {
+	Read   Limit
+	Write  Limit
+	Routes map[string]Limit
+	Exempt []string
+}
+
+func ParseRoute(value string) (string, Limit, error) {
+	route, limit, ok := strings.Cut(value, "=")
+	method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+	if !ok || !hasPath || method == "" || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+		return "", Limit{}, fmt.Errorf("invalid route limit %q, expected METHOD /path=requests/window", value)
+	}
+	parsed, err := ParseLimit(limit)
+	if err != nil {
+		return "", Limit{}, err
+	}
+	return strings.ToUpper(method) + " " + strings.TrimSpace(path), parsed, nil
+}
+
+func (r Rules) match(method, route string) (string, Limit, bool) {
+	for _, exempt := range r.Exempt {
+		if route == exempt {
+			return "", Limit{}, false
+		}
+	}
+	if limit, ok := r.Routes[method+" "+route]; ok {
+		return method + " " + route, limit, true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "read", r.Read, true
+	}
+	return "write", r.Write, true
+}
+
+func ClientKey(c *gin.Context) string {
+	if user := c.GetString(UserKey); user != "" {
+		return "user:" + user
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func Middleware(store Store, rules Rules, key KeyFunc) gin.HandlerFunc {
+	if key == nil {
+		key = ClientKey
+	}
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		scope, limit, ok := rules.match(c.Request.Method, route)
+		if !ok || limit.Unlimited() {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), key(c)+"|"+scope, limit, time.Now())
+		if err != nil {
+			slog.Warn("Rate limit store unavailable, allowing request", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(int(limit.Per.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+
+		if !result.Allowed {
+			retry := int(result.RetryAfter.Seconds())
+			c.Header("Retry-After", strconv.Itoa(retry))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many requests",
+				"message": fmt.Sprintf("Rate limit of %s exceeded, retry in %ds", limit, retry),
+			})
+			return
+		}
+		c.Next()
+	}
+}
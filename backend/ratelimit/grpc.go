@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PeerKeyFunc func(ctx context.Context) string
+
+func PeerKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "ip:" + p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+func (r Rules) matchRPC(fullMethod string) (string, Limit) {
+	if limit, ok := r.Routes["GRPC "+fullMethod]; ok {
+		return "GRPC " + fullMethod, limit
+	}
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List") {
+		return "read", r.Read
+	}
+	return "write", r.Write
+}
+
+func UnaryInterceptor(store Store, rules Rules, key PeerKeyFunc) grpc.UnaryServerInterceptor {
+	check := rpcCheck(store, rules, key)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := check(ctx, info.FullMethod, func(md metadata.MD) { grpc.SetHeader(ctx, md) }); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamInterceptor(store Store, rules Rules, key PeerKeyFunc) grpc.StreamServerInterceptor {
+	check := rpcCheck(store, rules, key)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context(), info.FullMethod, func(md metadata.MD) { ss.SetHeader(md) }); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func rpcCheck(store Store, rules Rules, key PeerKeyFunc) func(context.Context, string, func(metadata.MD)) error {
+	if key == nil {
+		key = PeerKey
+	}
+	return func(ctx context.Context, fullMethod string, setHeader func(metadata.MD)) error {
+		scope, limit := rules.matchRPC(fullMethod)
+		if limit.Unlimited() {
+			return nil
+		}
+
+		result, err := store.Take(ctx, key(ctx)+"|"+scope, limit, time.Now())
+		if err != nil {
+			slog.Warn("Rate limit store unavailable, allowing request", "error", err)
+			return nil
+		}
+
+		md := metadata.Pairs(
+			"ratelimit-policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(int(limit.Per.Seconds())),
+			"ratelimit-limit", strconv.Itoa(result.Limit),
+			"ratelimit-remaining", strconv.Itoa(result.Remaining),
+			"ratelimit-reset", strconv.Itoa(int(result.Reset.Seconds())),
+		)
+		if !result.Allowed {
+			retry := int(result.RetryAfter.Seconds())
+			md.Set("retry-after", strconv.Itoa(retry))
+			setHeader(md)
+			return status.Errorf(codes.ResourceExhausted, "Too many requests: Rate limit of %s exceeded, retry in %ds", limit, retry)
+		}
+		setHeader(md)
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	for value, want := range map[string]Limit{
+		"60/1m":  {Requests: 60, Per: time.Minute},
+		"10/s":   {Requests: 10, Per: time.Second},
+		"5/90s":  {Requests: 5, Per: 90 * time.Second},
+		"off":    {},
+		" 1/h ":  {Requests: 1, Per: time.Hour},
+		"100/2h": {Requests: 100, Per: 2 * time.Hour},
+	} {
+		got, err := ParseLimit(value)
+		if err != nil || got != want {
+			t.Errorf("ParseLimit(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "60", "0/1m", "-1/1m", "ten/1m", "10/0s", "10/soon"} {
+		if _, err := ParseLimit(value); err == nil {
+			t.Errorf("ParseLimit(%q) should fail", value)
+		}
+	}
+	if got := (Limit{Requests: 5, Per: 90 * time.Second}).String(); got != "5/90s" {
+		t.Errorf("unexpected String() %q", got)
+	}
+}
+
+func TestMemoryStoreRefillsAndEvicts(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Per: 10 * time.Second}
+	now := time.Now()
+	ctx := context.Background()
+
+	for i, want := range []bool{true, true, false} {
+		result, err := store.Take(ctx, "ip:1", limit, now)
+		if err != nil || result.Allowed != want {
+			t.Fatalf("request %d: allowed=%v err=%v, want %v", i, result.Allowed, err, want)
+		}
+	}
+
+	result, _ := store.Take(ctx, "ip:1", limit, now)
+	if result.RetryAfter != 5*time.Second || result.Reset != 10*time.Second || result.Remaining != 0 {
+		t.Errorf("unexpected denial %+v", result)
+	}
+
+	result, _ = store.Take(ctx, "ip:1", limit, now.Add(5*time.Second))
+	if !result.Allowed || result.Remaining != 0 {
+		t.Errorf("one token should refill after 5s, got %+v", result)
+	}
+
+	store.Take(ctx, "ip:2", limit, now.Add(5*time.Second))
+	if store.Len() != 2 {
+		t.Fatalf("expected 2 buckets, got %d", store.Len())
+	}
+	store.Take(ctx, "ip:3", limit, now.Add(2*time.Minute))
+	if store.Len() != 1 {
+		t.Errorf("idle full buckets should be evicted, %d left", store.Len())
+	}
+}
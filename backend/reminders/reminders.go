@@ -0,0 +1,182 @@
+package reminders
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+)
+
+type target struct {
+	Type      string
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+func StartScheduler(ctx context.Context, cfg config.Reminders) <-chan struct{} {
+	slog.Info("Reminder scheduler started", "window", cfg.Window.String(), "interval", cfg.ScanInterval.String())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(cfg.ScanInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := Scan(cfg.Window, time.Now()); err != nil {
+				slog.Error("Reminder scan failed", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				slog.Info("Reminder scheduler stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
+
+func Scan(window time.Duration, now time.Time) (int, error) {
+	cutoff := now.Add(-window)
+
+	var members []models.TeamMember
+	if err := database.DB.Find(&members).Error; err != nil {
+		return 0, err
+	}
+	var teams []models.Team
+	if err := database.DB.Find(&teams).Error; err != nil {
+		return 0, err
+	}
+
+	targets := make([]target, 0, len(members)+len(teams))
+	for _, member := range members {
+		targets = append(targets, target{Type: "member", ID: member.ID, Name: member.Name, CreatedAt: member.CreatedAt})
+	}
+	for _, team := range teams {
+		targets = append(targets, target{Type: "team", ID: team.ID, Name: team.Name, CreatedAt: team.CreatedAt})
+	}
+
+	recent := map[string]bool{}
+	var recentFeedbacks []models.Feedback
+	if err := database.DB.Model(&models.Feedback{}).
+		Distinct("target_type", "target_id").
+		Where("created_at >= ?", cutoff).
+		Find(&recentFeedbacks).Error; err != nil {
+		return 0, err
+	}
+	for _, feedback := range recentFeedbacks {
+		recent[feedback.TargetType+":"+feedback.TargetID] = true
+	}
+
+	var openReminders []models.Reminder
+	if err := database.DB.Where("status = ?", StatusOpen).Find(&openReminders).Error; err != nil {
+		return 0, err
+	}
+	var resolved []string
+	open := make(map[string]string, len(openReminders))
+	for _, reminder := range openReminders {
+		key := reminder.TargetType + ":" + reminder.TargetID
+		if duplicate, ok := open[key]; ok {
+			resolved = append(resolved, duplicate)
+		}
+		open[key] = reminder.ID
+	}
+
+	var stale []target
+	for _, t := range targets {
+		key := t.Type + ":" + t.ID
+		id, hasOpen := open[key]
+		delete(open, key)
+
+		if recent[key] || !t.CreatedAt.Before(cutoff) {
+			if hasOpen {
+				resolved = append(resolved, id)
+			}
+			continue
+		}
+
+		if !hasOpen {
+			stale = append(stale, t)
+		}
+	}
+
+	lastFeedback, err := lastFeedbackAt(stale, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for _, t := range stale {
+		key := t.Type + ":" + t.ID
+		reminder := models.Reminder{
+			ID:         uuid.New().String(),
+			TargetType: t.Type,
+			TargetID:   t.ID,
+			TargetName: t.Name,
+			Status:     StatusOpen,
+			OpenKey:    &key,
+		}
+		if last, ok := lastFeedback[key]; ok {
+			reminder.LastFeedbackAt = &last
+		}
+		result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&reminder)
+		if result.Error != nil {
+			return created, result.Error
+		}
+		created += int(result.RowsAffected)
+	}
+
+	for _, id := range open {
+		resolved = append(resolved, id)
+	}
+	if len(resolved) > 0 {
+		err := database.DB.Model(&models.Reminder{}).Where("id IN ?", resolved).
+			Updates(map[string]interface{}{"status": StatusResolved, "resolved_at": now, "open_key": nil}).Error
+		if err != nil {
+			return created, err
+		}
+	}
+
+	if created > 0 {
+		slog.Info("Created reminders for targets without recent feedback", "count", created, "cutoff", cutoff.Format(time.RFC3339))
+	}
+	return created, nil
+}
+
+func lastFeedbackAt(targets []target, cutoff time.Time) (map[string]time.Time, error) {
+	last := map[string]time.Time{}
+	if len(targets) == 0 {
+		return last, nil
+	}
+	ids := make([]string, 0, len(targets))
+	for _, t := range targets {
+		ids = append(ids, t.ID)
+	}
+
+	latest := database.DB.Model(&models.Feedback{}).
+		Select("target_type, target_id, MAX(created_at) AS created_at").
+		Where("target_id IN ? AND created_at < ?", ids, cutoff).
+		Group("target_type, target_id")
+	var feedbacks []models.Feedback
+	if err := database.DB.Model(&models.Feedback{}).
+		Select("feedbacks.target_type", "feedbacks.target_id", "feedbacks.created_at").
+		Joins("JOIN (?) AS latest ON latest.target_type = feedbacks.target_type AND latest.target_id = feedbacks.target_id AND latest.created_at = feedbacks.created_at", latest).
+		Find(&feedbacks).Error; err != nil {
+		return nil, err
+	}
+	for _, feedback := range feedbacks {
+		last[feedback.TargetType+":"+feedback.TargetID] = feedback.CreatedAt
+	}
+	return last, nil
+}
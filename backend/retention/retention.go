@@ -0,0 +1,155 @@
+package retention
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"gorm.io/gorm"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type Purge struct {
+	FeedbackID string    `json:"feedback_id"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	Category   string    `json:"category"`
+	Policy     string    `json:"policy"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PolicyReport struct {
+	Policy  string    `json:"policy"`
+	Cutoff  time.Time `json:"cutoff"`
+	Expired int64     `json:"expired"`
+	Held    int64     `json:"held"`
+	Purged  int64     `json:"purged"`
+}
+
+type Report struct {
+	DryRun   bool           `json:"dry_run"`
+	RanAt    time.Time      `json:"ran_at"`
+	Policies []PolicyReport `json:"policies"`
+}
+
+func StartScheduler(ctx context.Context, cfg config.Retention) <-chan struct{} {
+	slog.Info("Retention scheduler started", "interval", cfg.Interval.String(), "policies", cfg.Policies, "dry_run", cfg.DryRun)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			report, err := Enforce(ctx, cfg.ParsedPolicies(), time.Now(), cfg.BatchSize, cfg.DryRun)
+			if err != nil {
+				slog.Error("Retention run failed", "error", err)
+			}
+			for _, policy := range report.Policies {
+				if policy.Expired > 0 {
+					slog.Info("Retention policy applied", "policy", policy.Policy, "cutoff", policy.Cutoff.Format(time.RFC3339), "expired", policy.Expired, "held", policy.Held, "purged", policy.Purged, "dry_run", report.DryRun)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				slog.Info("Retention scheduler stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
+
+func Enforce(ctx context.Context, policies []config.RetentionPolicy, now time.Time, batchSize int, dryRun bool) (Report, error) {
+	report := Report{DryRun: dryRun, RanAt: now.UTC()}
+
+	policies = append([]config.RetentionPolicy(nil), policies...)
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Category != "" && policies[j].Category == ""
+	})
+	categories := map[string][]string{}
+	for _, policy := range policies {
+		if policy.Category != "" {
+			categories[policy.TargetType] = append(categories[policy.TargetType], policy.Category)
+		}
+	}
+
+	for _, policy := range policies {
+		result := PolicyReport{Policy: policy.Key(), Cutoff: now.Add(-policy.MaxAge).UTC()}
+		expired := func(db *gorm.DB) *gorm.DB {
+			db = db.Where("target_type = ? AND created_at < ?", policy.TargetType, result.Cutoff)
+			if policy.Category != "" {
+				return db.Where("category = ?", policy.Category)
+			}
+			if len(categories[policy.TargetType]) > 0 {
+				return db.Where("(category IS NULL OR category NOT IN ?)", categories[policy.TargetType])
+			}
+			return db
+		}
+		purgeable := func(db *gorm.DB) *gorm.DB {
+			held := database.DB.WithContext(ctx).Model(&models.TeamMember{}).Select("id").Where("legal_hold = ?", true)
+			db = db.Where("legal_hold = ?", false).Where("(author_id IS NULL OR author_id NOT IN (?))", held)
+			if policy.TargetType == "member" {
+				db = db.Where("target_id NOT IN (?)", held)
+			}
+			return db
+		}
+
+		var purgeableCount int64
+		if err := database.DB.WithContext(ctx).Model(&models.Feedback{}).Scopes(expired).Count(&result.Expired).Error; err != nil {
+			return report, err
+		}
+		if err := database.DB.WithContext(ctx).Model(&models.Feedback{}).Scopes(expired, purgeable).Count(&purgeableCount).Error; err != nil {
+			return report, err
+		}
+		result.Held = result.Expired - purgeableCount
+
+		if dryRun {
+			result.Purged = purgeableCount
+			report.Policies = append(report.Policies, result)
+			continue
+		}
+
+		for {
+			var batch []Purge
+			err := database.DB.WithContext(ctx).Model(&models.Feedback{}).Scopes(expired, purgeable).
+				Select("id AS feedback_id, target_type, target_id, category, created_at").
+				Order("created_at").Limit(batchSize).Find(&batch).Error
+			if err != nil || len(batch) == 0 {
+				report.Policies = append(report.Policies, result)
+				if err != nil {
+					return report, err
+				}
+				break
+			}
+
+			ids := make([]string, 0, len(batch))
+			for _, purge := range batch {
+				ids = append(ids, purge.FeedbackID)
+			}
+			err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				if err := tx.Delete(&models.FeedbackSearchToken{}, "feedback_id IN ?", ids).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&models.Feedback{}, "id IN ?", ids).Error
+			})
+			if err != nil {
+				report.Policies = append(report.Policies, result)
+				return report, err
+			}
+
+			result.Purged += int64(len(batch))
+			for _, purge := range batch {
+				purge.Policy = policy.Key()
+				events.Emit(events.FeedbackPurged, purge)
+			}
+		}
+	}
+	return report, nil
+}
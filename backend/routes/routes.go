@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"coaching-backend/handlers"
+	"coaching-backend/health"
+	"coaching-backend/metrics"
+	"coaching-backend/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+func Register(r *gin.Engine) {
+	api := r.Group("/api/v1")
+	{
+		members := api.Group("/members")
+		{
+			members.POST("", handlers.CreateTeamMember)
+			members.GET("", handlers.GetTeamMembers)
+			members.GET("/:id", handlers.GetTeamMember)
+			members.PUT("/:id", handlers.UpdateTeamMember)
+			members.DELETE("/:id", handlers.DeleteTeamMember)
+			members.GET("/:id/notifications", handlers.GetNotificationPreferences)
+			members.PUT("/:id/notifications", handlers.UpdateNotificationPreferences)
+			members.GET("/:id/export", handlers.ExportTeamMember)
+			members.POST("/:id/erase", handlers.EraseTeamMember)
+			members.PUT("/:id/legal-hold", handlers.SetMemberLegalHold)
+			members.POST("/:id/avatar", handlers.UploadMemberAvatar)
+			members.DELETE("/:id/avatar", handlers.DeleteMemberAvatar)
+		}
+
+		teams := api.Group("/teams")
+		{
+			teams.POST("", handlers.CreateTeam)
+			teams.GET("", handlers.GetTeams)
+			teams.GET("/:id", handlers.GetTeam)
+			teams.PUT("/:id", handlers.UpdateTeam)
+			teams.DELETE("/:id", handlers.DeleteTeam)
+			teams.POST("/:id/logo", handlers.UploadTeamLogo)
+			teams.DELETE("/:id/logo", handlers.DeleteTeamLogo)
+			teams.POST("/assign", handlers.AssignMemberToTeam)
+			teams.DELETE("/members/:memberID", handlers.RemoveMemberFromTeam)
+			teams.GET("/ws", handlers.TeamCollaboration)
+		}
+
+		feedbacks := api.Group("/feedbacks")
+		{
+			feedbacks.POST("", handlers.CreateFeedback)
+			feedbacks.GET("", handlers.GetFeedbacks)
+			feedbacks.GET("/:id", handlers.GetFeedback)
+			feedbacks.PUT("/:id", handlers.UpdateFeedback)
+			feedbacks.DELETE("/:id", handlers.DeleteFeedback)
+			feedbacks.PUT("/:id/legal-hold", handlers.SetFeedbackLegalHold)
+			feedbacks.PUT("/:id/goal", handlers.SetFeedbackGoal)
+		}
+
+		goals := api.Group("/goals")
+		{
+			goals.POST("", handlers.CreateGoal)
+			goals.GET("", handlers.GetGoals)
+			goals.GET("/:id", handlers.GetGoal)
+			goals.PUT("/:id", handlers.UpdateGoal)
+			goals.DELETE("/:id", handlers.DeleteGoal)
+			goals.GET("/:id/feedbacks", handlers.GetGoalFeedbacks)
+		}
+
+		api.GET("/reminders", handlers.GetReminders)
+		api.GET("/events", handlers.StreamEvents)
+		api.GET("/openapi.json", openapi.Serve)
+
+		hooks := api.Group("/webhooks")
+		{
+			hooks.POST("", handlers.CreateWebhook)
+			hooks.GET("", handlers.GetWebhooks)
+			hooks.GET("/:id", handlers.GetWebhook)
+			hooks.PUT("/:id", handlers.UpdateWebhook)
+			hooks.DELETE("/:id", handlers.DeleteWebhook)
+			hooks.GET("/:id/deliveries", handlers.GetWebhookDeliveries)
+		}
+
+		admin := api.Group("/admin")
+		{
+			admin.GET("/config", handlers.GetConfig)
+			admin.GET("/retention", handlers.GetRetentionReport)
+			admin.POST("/retention/run", handlers.RunRetention)
+		}
+	}
+
+	r.POST("/graphql", handlers.GraphQL)
+	r.GET("/metrics", metrics.Handler())
+	r.GET("/media/*key", handlers.ServeMedia)
+	r.GET("/avatars/members/:file", handlers.GeneratedMemberAvatar)
+	r.GET("/avatars/teams/:file", handlers.GeneratedTeamLogo)
+
+	r.GET("/health", health.Health)
+	r.GET("/livez", health.Livez)
+	r.GET("/readyz", health.Readyz)
+}
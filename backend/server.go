@@ -0,0 +1,76 @@
+package main
+
+import (
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/health"
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+func newHTTPServer(addr string, handler http.Handler, cfg config.HTTP) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+func drain(cfg config.HTTP, server *http.Server, grpcServer *grpc.Server, stopWorkers context.CancelFunc, workers ...<-chan struct{}) error {
+	health.SetDraining(true)
+	if cfg.DrainDelay > 0 {
+		slog.Info("Draining before shutdown", "delay", cfg.DrainDelay.String())
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("http server: %w", err))
+	}
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+		errs = append(errs, fmt.Errorf("grpc server: %w", ctx.Err()))
+	}
+	slog.Info("Servers stopped")
+
+	stopWorkers()
+	for _, done := range workers {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("background workers: %w", ctx.Err()))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	slog.Info("Background workers stopped")
+
+	if err := database.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("database: %w", err))
+	} else {
+		slog.Info("Database connections closed")
+	}
+
+	return errors.Join(errs...)
+}
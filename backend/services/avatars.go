@@ -0,0 +1,96 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/images"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"strings"
+)
+
+var GeneratedFormats = map[string]string{
+	"svg": "image/svg+xml",
+	"png": "image/png",
+}
+
+type GeneratedImage struct {
+	Data        []byte
+	ContentType string
+	Version     string
+}
+
+func generatedURL(kind, id string) string {
+	return strings.TrimSuffix(MediaBaseURL, "/") + "/avatars/" + kind + "/" + id + ".svg"
+}
+
+func isGeneratedURL(url string) bool {
+	return strings.HasPrefix(url, strings.TrimSuffix(MediaBaseURL, "/")+"/avatars/")
+}
+
+func ResolveMemberPicture(member *models.TeamMember) {
+	if member.Picture == "" {
+		member.Picture = generatedURL("members", member.ID)
+	}
+}
+
+func ResolveTeamLogo(team *models.Team) {
+	if team.Logo == "" {
+		team.Logo = generatedURL("teams", team.ID)
+	}
+	for i := range team.Members {
+		ResolveMemberPicture(&team.Members[i])
+	}
+}
+
+func validateGenerated(format string, size int) *Error {
+	if _, ok := GeneratedFormats[format]; !ok {
+		return invalidParameterError("format must be svg or png")
+	}
+	if size < images.MinGeneratedSize || size > images.MaxGeneratedSize {
+		return invalidParameterError(fmt.Sprintf("size must be between %d and %d", images.MinGeneratedSize, images.MaxGeneratedSize))
+	}
+	return nil
+}
+
+func renderGenerated(format, version string, svg func() []byte, raster func() ([]byte, error)) (GeneratedImage, *Error) {
+	image := GeneratedImage{ContentType: GeneratedFormats[format], Version: version}
+	if format == "svg" {
+		image.Data = svg()
+		return image, nil
+	}
+	data, err := raster()
+	if err != nil {
+		return image, storageError("Failed to render image", err)
+	}
+	image.Data = data
+	return image, nil
+}
+
+func MemberInitialsAvatar(ctx context.Context, id, format string, size int) (GeneratedImage, *Error) {
+	if err := validateGenerated(format, size); err != nil {
+		return GeneratedImage{}, err
+	}
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).Select("id", "name").First(&member, "id = ?", id).Error; err != nil {
+		return GeneratedImage{}, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+
+	return renderGenerated(format, member.Name,
+		func() []byte { return images.InitialsSVG(member.Name, size) },
+		func() ([]byte, error) { return images.InitialsPNG(member.Name, size) })
+}
+
+func TeamIdenticon(ctx context.Context, id, format string, size int) (GeneratedImage, *Error) {
+	if err := validateGenerated(format, size); err != nil {
+		return GeneratedImage{}, err
+	}
+	var team models.Team
+	if err := database.DB.WithContext(ctx).Select("id").First(&team, "id = ?", id).Error; err != nil {
+		return GeneratedImage{}, notFoundError("Team not found", "The requested team does not exist", err)
+	}
+
+	return renderGenerated(format, team.ID,
+		func() []byte { return images.IdenticonSVG(team.ID, size) },
+		func() ([]byte, error) { return images.IdenticonPNG(team.ID, size) })
+}
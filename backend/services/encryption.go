@@ -0,0 +1,188 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/models"
+	"context"
+	"gorm.io/gorm"
+)
+
+type ReencryptResult struct {
+	Scanned     int `json:"scanned"`
+	Reencrypted int `json:"reencrypted"`
+	Reindexed   int `json:"reindexed"`
+}
+
+func searchFeedbacks(ctx context.Context, query *gorm.DB, q string) (*gorm.DB, *Error) {
+	terms := encryption.Terms(q)
+	if len(terms) == 0 {
+		return nil, invalidParameterError("q must contain at least one word of two or more letters or digits")
+	}
+
+	if !encryption.Enabled() {
+		for _, term := range terms {
+			query = query.Where("LOWER(content) LIKE ?", "%"+term+"%")
+		}
+		return query, nil
+	}
+	if !encryption.SearchIndexEnabled() {
+		return nil, invalidParameterError("Feedback content is encrypted and the search index is disabled, set ENCRYPTION_SEARCH_INDEX=true to enable q")
+	}
+
+	for _, term := range terms {
+		tokens, err := encryption.QueryTokens(ctx, term)
+		if err != nil {
+			return nil, encryptionError("Failed to derive search tokens", err)
+		}
+		matches := database.DB.WithContext(ctx).Model(&models.FeedbackSearchToken{}).Select("feedback_id").Where("token IN ?", tokens)
+		query = query.Where("id IN (?)", matches)
+	}
+	return query, nil
+}
+
+func indexFeedback(ctx context.Context, tx *gorm.DB, feedback models.Feedback) error {
+	if err := tx.Delete(&models.FeedbackSearchToken{}, "feedback_id = ?", feedback.ID).Error; err != nil {
+		return err
+	}
+
+	tokens, err := encryption.IndexTokens(ctx, feedback.Content)
+	if err != nil || len(tokens) == 0 {
+		return err
+	}
+	rows := make([]models.FeedbackSearchToken, 0, len(tokens))
+	for _, token := range tokens {
+		rows = append(rows, models.FeedbackSearchToken{FeedbackID: feedback.ID, Token: token})
+	}
+	return tx.Create(&rows).Error
+}
+
+func ReencryptFeedbacks(ctx context.Context, batchSize int, reindex, dryRun bool) (ReencryptResult, *Error) {
+	var result ReencryptResult
+	if !encryption.Enabled() {
+		return result, invalidParameterError("No encryption keys are configured, set ENCRYPTION_KEY_FILE")
+	}
+	reindex = reindex && encryption.SearchIndexEnabled()
+
+	lastID := ""
+	for {
+		var rows []struct {
+			ID      string
+			Content string
+		}
+		err := database.DB.WithContext(ctx).Table("feedbacks").Select("id, content").
+			Where("id > ?", lastID).Order("id").Limit(batchSize).Find(&rows).Error
+		if err != nil {
+			return result, databaseError("Failed to scan feedbacks", err)
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		var ids []string
+		rotate := map[string]bool{}
+		for _, row := range rows {
+			result.Scanned++
+			if encryption.NeedsRotation(row.Content) {
+				rotate[row.ID] = true
+			}
+			if rotate[row.ID] || reindex {
+				ids = append(ids, row.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if dryRun {
+			result.Reencrypted += len(rotate)
+			if encryption.SearchIndexEnabled() {
+				result.Reindexed += len(ids)
+			}
+			continue
+		}
+
+		err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var feedbacks []models.Feedback
+			if err := tx.Where("id IN ?", ids).Find(&feedbacks).Error; err != nil {
+				return err
+			}
+			for _, feedback := range feedbacks {
+				if rotate[feedback.ID] {
+					if err := tx.Model(&feedback).Select("content").Updates(models.Feedback{ID: feedback.ID, Content: feedback.Content}).Error; err != nil {
+						return err
+					}
+				}
+				if encryption.SearchIndexEnabled() {
+					if err := indexFeedback(ctx, tx, feedback); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return result, databaseError("Failed to re-encrypt feedbacks", err)
+		}
+		result.Reencrypted += len(rotate)
+		if encryption.SearchIndexEnabled() {
+			result.Reindexed += len(ids)
+		}
+	}
+}
+
+func ReencryptWebhookDeliveries(ctx context.Context, batchSize int, dryRun bool) (ReencryptResult, *Error) {
+	var result ReencryptResult
+	if !encryption.Enabled() {
+		return result, invalidParameterError("No encryption keys are configured, set ENCRYPTION_KEY_FILE")
+	}
+
+	lastID := ""
+	for {
+		var rows []struct {
+			ID      string
+			Payload string
+		}
+		err := database.DB.WithContext(ctx).Table("webhook_deliveries").Select("id, payload").
+			Where("id > ?", lastID).Order("id").Limit(batchSize).Find(&rows).Error
+		if err != nil {
+			return result, databaseError("Failed to scan webhook deliveries", err)
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		var ids []string
+		for _, row := range rows {
+			result.Scanned++
+			if encryption.NeedsRotation(row.Payload) {
+				ids = append(ids, row.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if dryRun {
+			result.Reencrypted += len(ids)
+			continue
+		}
+
+		err = database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var deliveries []models.WebhookDelivery
+			if err := tx.Where("id IN ?", ids).Find(&deliveries).Error; err != nil {
+				return err
+			}
+			for _, delivery := range deliveries {
+				if err := tx.Model(&delivery).Select("payload").Updates(models.WebhookDelivery{ID: delivery.ID, Payload: delivery.Payload}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return result, databaseError("Failed to re-encrypt webhook deliveries", err)
+		}
+		result.Reencrypted += len(ids)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"net/http"
+)
+
+type Error struct {
+	Status  int
+	Title   string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   e.Title,
+		"status": e.Status,
+	}
+}
+
+func validationError(err error) *Error {
+	return &Error{
+		Status:  http.StatusBadRequest,
+		Title:   "Validation failed",
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
+func invalidParameterError(message string) *Error {
+	return &Error{
+		Status:  http.StatusBadRequest,
+		Title:   "Invalid parameter",
+		Message: message,
+	}
+}
+
+func databaseError(message string, err error) *Error {
+	return &Error{
+		Status:  http.StatusInternalServerError,
+		Title:   "Database error",
+		Message: message,
+		Err:     err,
+	}
+}
+
+func notFoundError(title, message string, err error) *Error {
+	return &Error{
+		Status:  http.StatusNotFound,
+		Title:   title,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func conflictError(title, message string, err error) *Error {
+	return &Error{
+		Status:  http.StatusConflict,
+		Title:   title,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func encryptionError(message string, err error) *Error {
+	return &Error{
+		Status:  http.StatusInternalServerError,
+		Title:   "Encryption error",
+		Message: message,
+		Err:     err,
+	}
+}
+
+func unsupportedMediaError(message string, err error) *Error {
+	return &Error{
+		Status:  http.StatusUnsupportedMediaType,
+		Title:   "Unsupported media type",
+		Message: message,
+		Err:     err,
+	}
+}
+
+func storageError(message string, err error) *Error {
+	return &Error{
+		Status:  http.StatusInternalServerError,
+		Title:   "Storage error",
+		Message: message,
+		Err:     err,
+	}
+}
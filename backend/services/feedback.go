@@ -0,0 +1,192 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+)
+
+var categoryRegex = regexp.MustCompile(`^[a-z0-9_-]{0,50}$`)
+
+func validateFeedback(feedback *models.Feedback) error {
+	if strings.TrimSpace(feedback.Content) == "" {
+		return fmt.Errorf("feedback content is required")
+	}
+	if len(strings.TrimSpace(feedback.Content)) < 5 {
+		return fmt.Errorf("feedback must be at least 5 characters")
+	}
+	if len(strings.TrimSpace(feedback.Content)) > 1000 {
+		return fmt.Errorf("feedback must be less than 1000 characters")
+	}
+	if feedback.TargetType != "team" && feedback.TargetType != "member" {
+		return fmt.Errorf("target type must be either 'team' or 'member'")
+	}
+	if strings.TrimSpace(feedback.TargetID) == "" {
+		return fmt.Errorf("target ID is required")
+	}
+	if !categoryRegex.MatchString(strings.ToLower(strings.TrimSpace(feedback.Category))) {
+		return fmt.Errorf("category must be at most 50 lowercase letters, digits, dashes or underscores")
+	}
+	return nil
+}
+
+type FeedbackFilter struct {
+	TargetType string
+	TargetID   string
+	GoalID     string
+	Query      string
+}
+
+func ListFeedbacks(ctx context.Context, filter FeedbackFilter) ([]models.Feedback, *Error) {
+	query := database.DB.WithContext(ctx)
+	if filter.TargetType != "" {
+		if filter.TargetType != "team" && filter.TargetType != "member" {
+			return nil, invalidParameterError("target_type must be either 'team' or 'member'")
+		}
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.GoalID != "" {
+		query = query.Where("goal_id = ?", filter.GoalID)
+	}
+	if filter.Query != "" {
+		var err *Error
+		if query, err = searchFeedbacks(ctx, query, filter.Query); err != nil {
+			return nil, err
+		}
+	}
+
+	var feedbacks []models.Feedback
+	if err := query.Order("created_at DESC").Find(&feedbacks).Error; err != nil {
+		return nil, databaseError("Failed to fetch feedbacks", err)
+	}
+	return feedbacks, nil
+}
+
+func GetFeedback(ctx context.Context, id string) (models.Feedback, *Error) {
+	var feedback models.Feedback
+	if err := database.DB.WithContext(ctx).First(&feedback, "id = ?", id).Error; err != nil {
+		return feedback, notFoundError("Feedback not found", "The requested feedback does not exist", err)
+	}
+	return feedback, nil
+}
+
+func CreateFeedback(ctx context.Context, feedback *models.Feedback) *Error {
+	if err := validateFeedback(feedback); err != nil {
+		return validationError(err)
+	}
+
+	if feedback.TargetType == "team" {
+		var team models.Team
+		if err := database.DB.WithContext(ctx).First(&team, "id = ?", feedback.TargetID).Error; err != nil {
+			return notFoundError("Team not found", "The target team does not exist", err)
+		}
+		feedback.TargetName = team.Name
+	} else if feedback.TargetType == "member" {
+		var member models.TeamMember
+		if err := database.DB.WithContext(ctx).First(&member, "id = ?", feedback.TargetID).Error; err != nil {
+			return notFoundError("Member not found", "The target team member does not exist", err)
+		}
+		feedback.TargetName = member.Name
+	}
+	if feedback.AuthorID != nil {
+		var author models.TeamMember
+		if err := database.DB.WithContext(ctx).First(&author, "id = ?", *feedback.AuthorID).Error; err != nil {
+			return notFoundError("Member not found", "The feedback author does not exist", err)
+		}
+	}
+	if feedback.GoalID != nil {
+		if err := checkFeedbackGoal(ctx, *feedback); err != nil {
+			return err
+		}
+	}
+
+	feedback.ID = uuid.New().String()
+	feedback.Content = strings.TrimSpace(feedback.Content)
+	feedback.Category = strings.ToLower(strings.TrimSpace(feedback.Category))
+	feedback.LegalHold = false
+
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(feedback).Error; err != nil {
+			return err
+		}
+		return indexFeedback(ctx, tx, *feedback)
+	})
+	if err != nil {
+		return databaseError("Failed to create feedback", err)
+	}
+
+	events.Emit(events.FeedbackCreated, *feedback)
+	return nil
+}
+
+func UpdateFeedback(ctx context.Context, id string, updateData models.Feedback) (models.Feedback, *Error) {
+	var feedback models.Feedback
+	if err := database.DB.WithContext(ctx).First(&feedback, "id = ?", id).Error; err != nil {
+		return feedback, notFoundError("Feedback not found", "The requested feedback does not exist", err)
+	}
+
+	if err := validateFeedback(&updateData); err != nil {
+		return feedback, validationError(err)
+	}
+	if updateData.GoalID != nil {
+		if err := checkFeedbackGoal(ctx, updateData); err != nil {
+			return feedback, err
+		}
+	}
+
+	updateData.Content = strings.TrimSpace(updateData.Content)
+	updateData.Category = strings.ToLower(strings.TrimSpace(updateData.Category))
+	updateData.ID = feedback.ID
+	updateData.AuthorID = nil
+	updateData.LegalHold = false
+
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&feedback).Updates(updateData).Error; err != nil {
+			return err
+		}
+		return indexFeedback(ctx, tx, feedback)
+	})
+	if err != nil {
+		return feedback, databaseError("Failed to update feedback", err)
+	}
+
+	events.Emit(events.FeedbackUpdated, feedback)
+	return feedback, nil
+}
+
+func DeleteFeedback(ctx context.Context, id string) *Error {
+	var deleted, held int64
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Feedback{}, "id = ? AND legal_hold = ?", id, false)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		if deleted == 0 {
+			return tx.Model(&models.Feedback{}).Where("id = ?", id).Count(&held).Error
+		}
+		return tx.Delete(&models.FeedbackSearchToken{}, "feedback_id = ?", id).Error
+	})
+	if err != nil {
+		return databaseError("Failed to delete feedback", err)
+	}
+
+	if held > 0 {
+		return conflictError("Feedback under legal hold", "The requested feedback is under legal hold and cannot be deleted", nil)
+	}
+	if deleted == 0 {
+		return notFoundError("Feedback not found", "The requested feedback does not exist", nil)
+	}
+
+	events.Emit(events.FeedbackDeleted, events.Ref{ID: id})
+	return nil
+}
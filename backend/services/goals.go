@@ -0,0 +1,260 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+const (
+	GoalNotStarted = "not_started"
+	GoalOnTrack    = "on_track"
+	GoalAtRisk     = "at_risk"
+	GoalCompleted  = "completed"
+	GoalCancelled  = "cancelled"
+)
+
+var GoalStatuses = []string{GoalNotStarted, GoalOnTrack, GoalAtRisk, GoalCompleted, GoalCancelled}
+
+func isGoalStatus(status string) bool {
+	for _, s := range GoalStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func validateGoal(goal *models.Goal) error {
+	if goal.OwnerType != "team" && goal.OwnerType != "member" {
+		return fmt.Errorf("owner type must be either 'team' or 'member'")
+	}
+	if strings.TrimSpace(goal.OwnerID) == "" {
+		return fmt.Errorf("owner ID is required")
+	}
+	if len(strings.TrimSpace(goal.Title)) < 3 {
+		return fmt.Errorf("title must be at least 3 characters")
+	}
+	if len(strings.TrimSpace(goal.Title)) > 200 {
+		return fmt.Errorf("title must be less than 200 characters")
+	}
+	if len(strings.TrimSpace(goal.Description)) > 5000 {
+		return fmt.Errorf("description must be less than 5000 characters")
+	}
+	if targetDate := strings.TrimSpace(goal.TargetDate); targetDate != "" {
+		if _, err := time.Parse(time.DateOnly, targetDate); err != nil {
+			return fmt.Errorf("target date must be formatted as YYYY-MM-DD")
+		}
+	}
+	if status := strings.ToLower(strings.TrimSpace(goal.Status)); status != "" && !isGoalStatus(status) {
+		return fmt.Errorf("status must be one of %s", strings.Join(GoalStatuses, ", "))
+	}
+	if goal.Progress < 0 || goal.Progress > 100 {
+		return fmt.Errorf("progress must be between 0 and 100")
+	}
+	return nil
+}
+
+func normalizeGoal(goal *models.Goal) {
+	goal.Title = strings.TrimSpace(goal.Title)
+	goal.Description = strings.TrimSpace(goal.Description)
+	goal.TargetDate = strings.TrimSpace(goal.TargetDate)
+	goal.Status = strings.ToLower(strings.TrimSpace(goal.Status))
+	if goal.Status == "" {
+		goal.Status = GoalNotStarted
+	}
+	if goal.Status == GoalCompleted {
+		goal.Progress = 100
+	}
+}
+
+func goalOwnerName(ctx context.Context, ownerType, ownerID string) (string, *Error) {
+	if ownerType == "team" {
+		var team models.Team
+		if err := database.DB.WithContext(ctx).First(&team, "id = ?", ownerID).Error; err != nil {
+			return "", notFoundError("Team not found", "The owning team does not exist", err)
+		}
+		return team.Name, nil
+	}
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", ownerID).Error; err != nil {
+		return "", notFoundError("Member not found", "The owning team member does not exist", err)
+	}
+	return member.Name, nil
+}
+
+type GoalFilter struct {
+	OwnerType string
+	OwnerID   string
+	Status    string
+}
+
+func ListGoals(ctx context.Context, filter GoalFilter) ([]models.Goal, *Error) {
+	query := database.DB.WithContext(ctx)
+	if filter.OwnerType != "" {
+		if filter.OwnerType != "team" && filter.OwnerType != "member" {
+			return nil, invalidParameterError("owner_type must be either 'team' or 'member'")
+		}
+		query = query.Where("owner_type = ?", filter.OwnerType)
+	}
+	if filter.OwnerID != "" {
+		query = query.Where("owner_id = ?", filter.OwnerID)
+	}
+	if filter.Status != "" {
+		if !isGoalStatus(filter.Status) {
+			return nil, invalidParameterError("status must be one of " + strings.Join(GoalStatuses, ", "))
+		}
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var goals []models.Goal
+	if err := query.Order("created_at DESC").Find(&goals).Error; err != nil {
+		return nil, databaseError("Failed to fetch goals", err)
+	}
+	return goals, nil
+}
+
+func GetGoal(ctx context.Context, id string) (models.Goal, *Error) {
+	var goal models.Goal
+	if err := database.DB.WithContext(ctx).First(&goal, "id = ?", id).Error; err != nil {
+		return goal, notFoundError("Goal not found", "The requested goal does not exist", err)
+	}
+	return goal, nil
+}
+
+func CreateGoal(ctx context.Context, goal *models.Goal) *Error {
+	if err := validateGoal(goal); err != nil {
+		return validationError(err)
+	}
+
+	ownerName, serviceErr := goalOwnerName(ctx, goal.OwnerType, goal.OwnerID)
+	if serviceErr != nil {
+		return serviceErr
+	}
+
+	goal.ID = uuid.New().String()
+	goal.OwnerName = ownerName
+	normalizeGoal(goal)
+
+	if err := database.DB.WithContext(ctx).Create(goal).Error; err != nil {
+		return databaseError("Failed to create goal", err)
+	}
+
+	events.Emit(events.GoalCreated, *goal)
+	return nil
+}
+
+func UpdateGoal(ctx context.Context, id string, updateData models.Goal) (models.Goal, *Error) {
+	var goal models.Goal
+	if err := database.DB.WithContext(ctx).First(&goal, "id = ?", id).Error; err != nil {
+		return goal, notFoundError("Goal not found", "The requested goal does not exist", err)
+	}
+
+	updateData.OwnerType = goal.OwnerType
+	updateData.OwnerID = goal.OwnerID
+	if err := validateGoal(&updateData); err != nil {
+		return goal, validationError(err)
+	}
+
+	normalizeGoal(&updateData)
+
+	err := database.DB.WithContext(ctx).Model(&goal).Select("title", "description", "target_date", "status", "progress").Updates(updateData).Error
+	if err != nil {
+		return goal, databaseError("Failed to update goal", err)
+	}
+
+	events.Emit(events.GoalUpdated, goal)
+	return goal, nil
+}
+
+func DeleteGoal(ctx context.Context, id string) *Error {
+	var deleted int64
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Goal{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return tx.Model(&models.Feedback{}).Where("goal_id = ?", id).Update("goal_id", nil).Error
+	})
+	if err != nil {
+		return databaseError("Failed to delete goal", err)
+	}
+
+	if deleted == 0 {
+		return notFoundError("Goal not found", "The requested goal does not exist", nil)
+	}
+
+	events.Emit(events.GoalDeleted, events.Ref{ID: id})
+	return nil
+}
+
+func deleteOwnerGoals(tx *gorm.DB, ownerType, ownerID string) ([]string, error) {
+	var ids []string
+	if err := tx.Model(&models.Goal{}).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if err := tx.Model(&models.Feedback{}).Where("goal_id IN ?", ids).Update("goal_id", nil).Error; err != nil {
+		return nil, err
+	}
+	return ids, tx.Delete(&models.Goal{}, "id IN ?", ids).Error
+}
+
+func ListGoalFeedbacks(ctx context.Context, id string) ([]models.Feedback, *Error) {
+	if _, err := GetGoal(ctx, id); err != nil {
+		return nil, err
+	}
+	return ListFeedbacks(ctx, FeedbackFilter{GoalID: id})
+}
+
+func checkFeedbackGoal(ctx context.Context, feedback models.Feedback) *Error {
+	goal, err := GetGoal(ctx, *feedback.GoalID)
+	if err != nil {
+		return err
+	}
+	if goal.OwnerType == feedback.TargetType && goal.OwnerID == feedback.TargetID {
+		return nil
+	}
+	if goal.OwnerType == "team" && feedback.TargetType == "member" {
+		var count int64
+		if err := database.DB.WithContext(ctx).Model(&models.TeamMember{}).Where("id = ? AND team_id = ?", feedback.TargetID, goal.OwnerID).Count(&count).Error; err != nil {
+			return databaseError("Failed to check goal owner", err)
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	return validationError(fmt.Errorf("goal must belong to the feedback target or, for member feedback, to the member's team"))
+}
+
+func SetFeedbackGoal(ctx context.Context, id string, goalID *string) (models.Feedback, *Error) {
+	var feedback models.Feedback
+	if err := database.DB.WithContext(ctx).First(&feedback, "id = ?", id).Error; err != nil {
+		return feedback, notFoundError("Feedback not found", "The requested feedback does not exist", err)
+	}
+
+	if goalID != nil {
+		linked := feedback
+		linked.GoalID = goalID
+		if err := checkFeedbackGoal(ctx, linked); err != nil {
+			return feedback, err
+		}
+	}
+
+	if err := database.DB.WithContext(ctx).Model(&feedback).Update("goal_id", goalID).Error; err != nil {
+		return feedback, databaseError("Failed to update feedback goal", err)
+	}
+	feedback.GoalID = goalID
+
+	events.Emit(events.FeedbackUpdated, feedback)
+	return feedback, nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+)
+
+func SetMemberLegalHold(ctx context.Context, id string, hold bool) (models.TeamMember, *Error) {
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+
+	if err := database.DB.WithContext(ctx).Model(&member).Update("legal_hold", hold).Error; err != nil {
+		return member, databaseError("Failed to update legal hold", err)
+	}
+
+	events.Emit(events.MemberUpdated, member)
+	return member, nil
+}
+
+func SetFeedbackLegalHold(ctx context.Context, id string, hold bool) (models.Feedback, *Error) {
+	var feedback models.Feedback
+	if err := database.DB.WithContext(ctx).First(&feedback, "id = ?", id).Error; err != nil {
+		return feedback, notFoundError("Feedback not found", "The requested feedback does not exist", err)
+	}
+
+	if err := database.DB.WithContext(ctx).Model(&feedback).Update("legal_hold", hold).Error; err != nil {
+		return feedback, databaseError("Failed to update legal hold", err)
+	}
+
+	events.Emit(events.FeedbackUpdated, feedback)
+	return feedback, nil
+}
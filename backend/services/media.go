@@ -0,0 +1,166 @@
+package services
+
+import (
+	"coaching-backend/blob"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/images"
+	"coaching-backend/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	Blobs        blob.Store = blob.NewLocal("media")
+	MediaBaseURL string
+)
+
+func MediaURL(key string) string {
+	return strings.TrimSuffix(MediaBaseURL, "/") + "/media/" + key
+}
+
+func isMediaURL(url string) bool {
+	return strings.HasPrefix(url, MediaURL(""))
+}
+
+func mediaKey(url, kind, ownerID string) (string, bool) {
+	prefix := MediaURL(kind + "/" + ownerID + "/")
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(url, prefix)
+	if name == "" || strings.ContainsAny(name, "/\\") || strings.Contains(name, "..") {
+		return "", false
+	}
+	return kind + "/" + ownerID + "/" + name, true
+}
+
+func checkMediaURL(field, url, current string) *Error {
+	if url == "" || url == current || !isMediaURL(url) {
+		return nil
+	}
+	return validationError(fmt.Errorf("%s must be uploaded through the image endpoint", field))
+}
+
+func storeImage(ctx context.Context, kind, ownerID string, data []byte) (string, *Error) {
+	thumbnails, err := images.Thumbnails(data, images.Sizes)
+	if errors.Is(err, images.ErrUnsupported) {
+		return "", unsupportedMediaError(err.Error(), err)
+	}
+	if err != nil {
+		return "", validationError(err)
+	}
+
+	sum := sha256.Sum256(data)
+	base := kind + "/" + ownerID + "/" + hex.EncodeToString(sum[:8])
+	var key string
+	for _, thumbnail := range thumbnails {
+		key = base + "-" + strconv.Itoa(thumbnail.Size) + thumbnail.Ext
+		if err := Blobs.Put(ctx, key, thumbnail.Data, thumbnail.ContentType); err != nil {
+			return "", storageError("Failed to store image", err)
+		}
+	}
+	return MediaURL(key), nil
+}
+
+func removeImages(ctx context.Context, kind, ownerID, url string) {
+	key, ok := mediaKey(url, kind, ownerID)
+	if !ok {
+		return
+	}
+	ext := path.Ext(key)
+	base := strings.TrimSuffix(key, "-"+strconv.Itoa(images.Sizes[len(images.Sizes)-1])+ext)
+	for _, size := range images.Sizes {
+		if err := Blobs.Delete(ctx, base+"-"+strconv.Itoa(size)+ext); err != nil {
+			slog.Warn("Failed to delete stored image", "key", base+"-"+strconv.Itoa(size)+ext, "error", err)
+		}
+	}
+}
+
+func SetMemberAvatar(ctx context.Context, id string, data []byte) (models.TeamMember, *Error) {
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+	if member.ErasedAt != nil {
+		return member, conflictError("Member erased", "The requested team member has been erased and cannot be updated", nil)
+	}
+	previous := member.Picture
+
+	url, serviceErr := storeImage(ctx, "avatars", member.ID, data)
+	if serviceErr != nil {
+		return member, serviceErr
+	}
+	if err := database.DB.WithContext(ctx).Model(&member).Update("picture", url).Error; err != nil {
+		removeImages(ctx, "avatars", member.ID, url)
+		return member, databaseError("Failed to update team member", err)
+	}
+	if previous != url {
+		removeImages(ctx, "avatars", member.ID, previous)
+	}
+
+	events.Emit(events.MemberUpdated, member)
+	return member, nil
+}
+
+func DeleteMemberAvatar(ctx context.Context, id string) (models.TeamMember, *Error) {
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+	previous := member.Picture
+
+	if err := database.DB.WithContext(ctx).Model(&member).Update("picture", "").Error; err != nil {
+		return member, databaseError("Failed to update team member", err)
+	}
+	removeImages(ctx, "avatars", member.ID, previous)
+
+	events.Emit(events.MemberUpdated, member)
+	return member, nil
+}
+
+func SetTeamLogo(ctx context.Context, id string, data []byte) (models.Team, *Error) {
+	var team models.Team
+	if err := database.DB.WithContext(ctx).Preload("Members").First(&team, "id = ?", id).Error; err != nil {
+		return team, notFoundError("Team not found", "The requested team does not exist", err)
+	}
+	previous := team.Logo
+
+	url, serviceErr := storeImage(ctx, "logos", team.ID, data)
+	if serviceErr != nil {
+		return team, serviceErr
+	}
+	if err := database.DB.WithContext(ctx).Model(&team).Update("logo", url).Error; err != nil {
+		removeImages(ctx, "logos", team.ID, url)
+		return team, databaseError("Failed to update team", err)
+	}
+	if previous != url {
+		removeImages(ctx, "logos", team.ID, previous)
+	}
+
+	events.Emit(events.TeamUpdated, team)
+	return team, nil
+}
+
+func DeleteTeamLogo(ctx context.Context, id string) (models.Team, *Error) {
+	var team models.Team
+	if err := database.DB.WithContext(ctx).Preload("Members").First(&team, "id = ?", id).Error; err != nil {
+		return team, notFoundError("Team not found", "The requested team does not exist", err)
+	}
+	previous := team.Logo
+
+	if err := database.DB.WithContext(ctx).Model(&team).Update("logo", "").Error; err != nil {
+		return team, databaseError("Failed to update team", err)
+	}
+	removeImages(ctx, "logos", team.ID, previous)
+
+	events.Emit(events.TeamUpdated, team)
+	return team, nil
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+const (
+	MinProficiency = 1
+	MaxProficiency = 5
+)
+
+var (
+	Levels     = []string{"intern", "junior", "mid", "senior", "staff", "principal"}
+	skillRegex = regexp.MustCompile(`^[a-z0-9+#. -]{1,50}$`)
+)
+
+func validateTeamMember(member *models.TeamMember) error {
+	if strings.TrimSpace(member.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(strings.TrimSpace(member.Name)) < 2 {
+		return fmt.Errorf("name must be at least 2 characters")
+	}
+	if len(strings.TrimSpace(member.Name)) > 50 {
+		return fmt.Errorf("name must be less than 50 characters")
+	}
+
+	if strings.TrimSpace(member.Email) == "" {
+		return fmt.Errorf("email is required")
+	}
+	emailRegex := regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+	if !emailRegex.MatchString(strings.TrimSpace(member.Email)) {
+		return fmt.Errorf("invalid email format")
+	}
+
+	if len(strings.TrimSpace(member.Title)) > 100 {
+		return fmt.Errorf("title must be less than 100 characters")
+	}
+	if level := strings.ToLower(strings.TrimSpace(member.Level)); level != "" && !isLevel(level) {
+		return fmt.Errorf("level must be one of %s", strings.Join(Levels, ", "))
+	}
+	if len(strings.TrimSpace(member.Location)) > 100 {
+		return fmt.Errorf("location must be less than 100 characters")
+	}
+	if timeZone := strings.TrimSpace(member.TimeZone); timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+			return fmt.Errorf("time zone must be an IANA name such as Europe/Berlin")
+		}
+	}
+	if startDate := strings.TrimSpace(member.StartDate); startDate != "" {
+		date, err := time.Parse(time.DateOnly, startDate)
+		if err != nil {
+			return fmt.Errorf("start date must be formatted as YYYY-MM-DD")
+		}
+		if date.After(time.Now()) {
+			return fmt.Errorf("start date must not be in the future")
+		}
+	}
+
+	if len(member.Skills) > 50 {
+		return fmt.Errorf("a member can have at most 50 skills")
+	}
+	seen := map[string]bool{}
+	for _, skill := range member.Skills {
+		name := strings.ToLower(strings.TrimSpace(skill.Name))
+		if !skillRegex.MatchString(name) {
+			return fmt.Errorf("skill %q must be 1 to 50 letters, digits, spaces or + # . - characters", skill.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("skill %q is listed more than once", skill.Name)
+		}
+		seen[name] = true
+		if skill.Proficiency < MinProficiency || skill.Proficiency > MaxProficiency {
+			return fmt.Errorf("proficiency for skill %q must be between %d and %d", skill.Name, MinProficiency, MaxProficiency)
+		}
+	}
+
+	return nil
+}
+
+func isLevel(level string) bool {
+	for _, l := range Levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeProfile(member *models.TeamMember) {
+	member.Name = strings.TrimSpace(member.Name)
+	member.Email = strings.TrimSpace(member.Email)
+	member.Picture = strings.TrimSpace(member.Picture)
+	if isGeneratedURL(member.Picture) {
+		member.Picture = ""
+	}
+	member.Title = strings.TrimSpace(member.Title)
+	member.Level = strings.ToLower(strings.TrimSpace(member.Level))
+	member.Location = strings.TrimSpace(member.Location)
+	member.TimeZone = strings.TrimSpace(member.TimeZone)
+	member.StartDate = strings.TrimSpace(member.StartDate)
+	for i := range member.Skills {
+		member.Skills[i].Name = strings.ToLower(strings.TrimSpace(member.Skills[i].Name))
+	}
+	member.LegalHold = false
+	member.ErasedAt = nil
+}
+
+type MemberFilter struct {
+	Skill          string
+	MinProficiency int
+	Level          string
+}
+
+func ListMembers(ctx context.Context, filter MemberFilter) ([]models.TeamMember, *Error) {
+	query := database.DB.WithContext(ctx)
+	if filter.Level != "" {
+		level := strings.ToLower(strings.TrimSpace(filter.Level))
+		if !isLevel(level) {
+			return nil, invalidParameterError("level must be one of " + strings.Join(Levels, ", "))
+		}
+		query = query.Where("level = ?", level)
+	}
+	skill := strings.ToLower(strings.TrimSpace(filter.Skill))
+	if skill != "" {
+		if !skillRegex.MatchString(skill) {
+			return nil, invalidParameterError("skill must be 1 to 50 letters, digits, spaces or + # . - characters")
+		}
+		query = query.Where("skills LIKE ?", `%"name":"`+skill+`"%`)
+	}
+	if filter.MinProficiency != 0 && (skill == "" || filter.MinProficiency < MinProficiency || filter.MinProficiency > MaxProficiency) {
+		return nil, invalidParameterError(fmt.Sprintf("min_proficiency must be between %d and %d and requires skill", MinProficiency, MaxProficiency))
+	}
+
+	var members []models.TeamMember
+	if err := query.Find(&members).Error; err != nil {
+		return nil, databaseError("Failed to fetch team members", err)
+	}
+	if skill == "" {
+		return members, nil
+	}
+
+	matched := make([]models.TeamMember, 0, len(members))
+	for _, member := range members {
+		for _, s := range member.Skills {
+			if s.Name == skill && s.Proficiency >= filter.MinProficiency {
+				matched = append(matched, member)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+func GetMember(ctx context.Context, id string) (models.TeamMember, *Error) {
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+	return member, nil
+}
+
+func CreateMember(ctx context.Context, member *models.TeamMember) *Error {
+	if err := validateTeamMember(member); err != nil {
+		return validationError(err)
+	}
+
+	member.ID = uuid.New().String()
+	normalizeProfile(member)
+	if err := checkMediaURL("picture", member.Picture, ""); err != nil {
+		return err
+	}
+
+	if err := database.DB.WithContext(ctx).Create(member).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return conflictError("Member already exists", "A member with this email already exists", err)
+		}
+		return databaseError("Failed to create team member", err)
+	}
+
+	events.Emit(events.MemberCreated, *member)
+	return nil
+}
+
+var MemberProfileFields = []string{"name", "email", "title", "level", "location", "time_zone", "start_date", "skills"}
+
+func UpdateMember(ctx context.Context, id string, updateData models.TeamMember, fields ...string) (models.TeamMember, *Error) {
+	if len(fields) == 0 {
+		fields = MemberProfileFields
+	}
+
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+	if member.ErasedAt != nil {
+		return member, conflictError("Member erased", "The requested team member has been erased and cannot be updated", nil)
+	}
+
+	if err := validateTeamMember(&updateData); err != nil {
+		return member, validationError(err)
+	}
+
+	normalizeProfile(&updateData)
+	if err := checkMediaURL("picture", updateData.Picture, member.Picture); err != nil {
+		return member, err
+	}
+
+	columns := append([]string{}, fields...)
+	if updateData.Picture != "" {
+		columns = append(columns, "picture")
+	}
+	if err := database.DB.WithContext(ctx).Model(&member).Select(columns).Updates(updateData).Error; err != nil {
+		return member, databaseError("Failed to update team member", err)
+	}
+
+	events.Emit(events.MemberUpdated, member)
+	return member, nil
+}
+
+func DeleteMember(ctx context.Context, id string) *Error {
+	var pictures []string
+	database.DB.WithContext(ctx).Model(&models.TeamMember{}).Where("id = ?", id).Pluck("picture", &pictures)
+
+	var deleted, held int64
+	var goals []string
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.TeamMember{}, "id = ? AND legal_hold = ?", id, false)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		if deleted == 0 {
+			return tx.Model(&models.TeamMember{}).Where("id = ?", id).Count(&held).Error
+		}
+		var err error
+		goals, err = deleteOwnerGoals(tx, "member", id)
+		return err
+	})
+	if err != nil {
+		return databaseError("Failed to delete team member", err)
+	}
+
+	if held > 0 {
+		return conflictError("Member under legal hold", "The requested team member is under legal hold and cannot be deleted", nil)
+	}
+	if deleted == 0 {
+		return notFoundError("Member not found", "The requested team member does not exist", nil)
+	}
+
+	for _, picture := range pictures {
+		removeImages(ctx, "avatars", id, picture)
+	}
+
+	for _, goal := range goals {
+		events.Emit(events.GoalDeleted, events.Ref{ID: goal})
+	}
+	events.Emit(events.MemberDeleted, events.Ref{ID: id})
+	return nil
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"bytes"
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+const (
+	ErasedMemberName  = "Erased member"
+	erasedEmailDomain = "erased.invalid"
+)
+
+var SigningKey []byte
+
+type MemberExport struct {
+	ExportedAt             time.Time                      `json:"exported_at"`
+	Member                 models.TeamMember              `json:"member"`
+	Team                   *models.Team                   `json:"team"`
+	Memberships            []models.AuditEntry            `json:"memberships"`
+	FeedbackReceived       []models.Feedback              `json:"feedback_received"`
+	FeedbackGiven          []models.Feedback              `json:"feedback_given"`
+	Goals                  []models.Goal                  `json:"goals"`
+	NotificationPreference *models.NotificationPreference `json:"notification_preference"`
+	AuditEntries           []models.AuditEntry            `json:"audit_entries"`
+}
+
+type ErasureReceipt struct {
+	ID                      string    `json:"id"`
+	MemberID                string    `json:"member_id"`
+	ErasedAt                time.Time `json:"erased_at"`
+	FeedbackAnonymized      int64     `json:"feedback_anonymized"`
+	FeedbackRelabelled      int64     `json:"feedback_relabelled"`
+	RemindersRelabelled     int64     `json:"reminders_relabelled"`
+	GoalsRelabelled         int64     `json:"goals_relabelled"`
+	AuditEntriesScrubbed    int64     `json:"audit_entries_scrubbed"`
+	WebhookPayloadsScrubbed int64     `json:"webhook_payloads_scrubbed"`
+	StreamEventsScrubbed    int64     `json:"stream_events_scrubbed"`
+	Signature               string    `json:"signature,omitempty"`
+}
+
+type memberScrubber struct {
+	id    string
+	email string
+}
+
+func (s memberScrubber) scrub(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v["email"]; ok && v["id"] == s.id {
+			for key, erased := range map[string]interface{}{
+				"name": ErasedMemberName, "email": s.email, "picture": "", "title": "", "level": "",
+				"location": "", "time_zone": "", "start_date": "", "skills": []interface{}{},
+			} {
+				if _, ok := v[key]; ok {
+					v[key] = erased
+				}
+			}
+			changed = true
+		}
+		if _, ok := v["target_name"]; ok && v["target_type"] == "member" && v["target_id"] == s.id {
+			v["target_name"] = ErasedMemberName
+			changed = true
+		}
+		if _, ok := v["owner_name"]; ok && v["owner_type"] == "member" && v["owner_id"] == s.id {
+			v["owner_name"] = ErasedMemberName
+			changed = true
+		}
+		if v["author_id"] == s.id {
+			v["author_id"] = nil
+			changed = true
+		}
+		for _, child := range v {
+			changed = s.scrub(child) || changed
+		}
+	case []interface{}:
+		for _, child := range v {
+			changed = s.scrub(child) || changed
+		}
+	}
+	return changed
+}
+
+func (s memberScrubber) scrubJSON(data []byte) ([]byte, bool) {
+	if !bytes.Contains(data, []byte(s.id)) {
+		return data, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || !s.scrub(value) {
+		return data, false
+	}
+	scrubbed, err := json.Marshal(value)
+	if err != nil {
+		return data, false
+	}
+	return scrubbed, true
+}
+
+func (s memberScrubber) scrubDeliveries(tx *gorm.DB) (int64, error) {
+	query := tx.Model(&models.WebhookDelivery{})
+	if !encryption.Enabled() {
+		query = query.Where("payload LIKE ?", "%"+s.id+"%")
+	}
+
+	var scrubbed int64
+	var deliveries []models.WebhookDelivery
+	result := query.FindInBatches(&deliveries, 200, func(batch *gorm.DB, _ int) error {
+		for _, delivery := range deliveries {
+			payload, changed := s.scrubJSON([]byte(delivery.Payload))
+			if !changed {
+				continue
+			}
+			err := tx.Model(&delivery).Select("payload").Updates(models.WebhookDelivery{ID: delivery.ID, Payload: string(payload)}).Error
+			if err != nil {
+				return err
+			}
+			scrubbed++
+		}
+		return nil
+	})
+	return scrubbed, result.Error
+}
+
+func (s memberScrubber) scrubAuditEntries(tx *gorm.DB) (int64, error) {
+	var entries []models.AuditEntry
+	err := tx.Where("entity_type = ? AND details LIKE ?", "feedback", `%"author_id":"`+s.id+`"%`).Find(&entries).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var scrubbed int64
+	for _, entry := range entries {
+		if entry.Details["author_id"] != s.id {
+			continue
+		}
+		delete(entry.Details, "author_id")
+		if err := tx.Model(&entry).Select("details").Updates(models.AuditEntry{ID: entry.ID, Details: entry.Details}).Error; err != nil {
+			return scrubbed, err
+		}
+		scrubbed++
+	}
+	return scrubbed, nil
+}
+
+func (s memberScrubber) scrubEvent(event events.Event) (events.Event, bool) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return event, false
+	}
+	scrubbed, changed := s.scrubJSON(data)
+	if changed {
+		event.Data = json.RawMessage(scrubbed)
+	}
+	return event, changed
+}
+
+func SignReceipt(key []byte, receipt ErasureReceipt) string {
+	receipt.Signature = ""
+	payload, _ := json.Marshal(receipt)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyReceipt(key []byte, receipt ErasureReceipt) bool {
+	return hmac.Equal([]byte(SignReceipt(key, receipt)), []byte(receipt.Signature))
+}
+
+func ExportMember(ctx context.Context, id string) (MemberExport, *Error) {
+	export := MemberExport{ExportedAt: time.Now().UTC()}
+	db := database.DB.WithContext(ctx)
+
+	if err := db.First(&export.Member, "id = ?", id).Error; err != nil {
+		return export, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+
+	if export.Member.TeamID != nil {
+		var teams []models.Team
+		if err := db.Where("id = ?", *export.Member.TeamID).Limit(1).Find(&teams).Error; err != nil {
+			return export, databaseError("Failed to fetch team", err)
+		}
+		if len(teams) > 0 {
+			export.Team = &teams[0]
+		}
+	}
+
+	if err := db.Where("target_type = ? AND target_id = ?", "member", id).Order("created_at").Find(&export.FeedbackReceived).Error; err != nil {
+		return export, databaseError("Failed to fetch feedback received", err)
+	}
+	if err := db.Where("author_id = ?", id).Order("created_at").Find(&export.FeedbackGiven).Error; err != nil {
+		return export, databaseError("Failed to fetch feedback given", err)
+	}
+	if err := db.Where("owner_type = ? AND owner_id = ?", "member", id).Order("created_at").Find(&export.Goals).Error; err != nil {
+		return export, databaseError("Failed to fetch goals", err)
+	}
+
+	var preferences []models.NotificationPreference
+	if err := db.Where("member_id = ?", id).Limit(1).Find(&preferences).Error; err != nil {
+		return export, databaseError("Failed to fetch notification preferences", err)
+	}
+	if len(preferences) > 0 {
+		export.NotificationPreference = &preferences[0]
+	}
+
+	feedbackIDs := []string{}
+	for _, feedback := range append(export.FeedbackReceived, export.FeedbackGiven...) {
+		feedbackIDs = append(feedbackIDs, feedback.ID)
+	}
+	query := db.Where("entity_type = ? AND entity_id = ?", "member", id)
+	if len(feedbackIDs) > 0 {
+		query = query.Or("entity_type = ? AND entity_id IN ?", "feedback", feedbackIDs)
+	}
+	if err := query.Order("created_at").Find(&export.AuditEntries).Error; err != nil {
+		return export, databaseError("Failed to fetch audit entries", err)
+	}
+
+	export.Memberships = []models.AuditEntry{}
+	for _, entry := range export.AuditEntries {
+		if entry.Action == events.MemberAssigned || entry.Action == events.MemberUnassigned {
+			export.Memberships = append(export.Memberships, entry)
+		}
+	}
+	return export, nil
+}
+
+func EraseMember(ctx context.Context, id string) (ErasureReceipt, *Error) {
+	receipt := ErasureReceipt{
+		ID:       uuid.New().String(),
+		MemberID: id,
+		ErasedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if len(SigningKey) == 0 {
+		return receipt, &Error{
+			Status:  http.StatusServiceUnavailable,
+			Title:   "Signing not configured",
+			Message: "Erasure receipts cannot be signed, set SIGNING_KEY or SIGNING_KEY_FILE",
+		}
+	}
+
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+		return receipt, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+	if member.ErasedAt != nil {
+		return receipt, conflictError("Member already erased", "The requested team member has already been erased", nil)
+	}
+	if member.LegalHold {
+		return receipt, conflictError("Member under legal hold", "The requested team member is under legal hold and cannot be erased", nil)
+	}
+
+	scrubber := memberScrubber{id: id, email: "erased-" + id + "@" + erasedEmailDomain}
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&member).Select("name", "email", "picture", "title", "level", "location", "time_zone", "start_date", "skills", "erased_at").Updates(models.TeamMember{
+			Name:     ErasedMemberName,
+			Email:    scrubber.email,
+			ErasedAt: &receipt.ErasedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Feedback{}).Where("author_id = ?", id).Update("author_id", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		receipt.FeedbackAnonymized = result.RowsAffected
+
+		result = tx.Model(&models.Feedback{}).Where("target_type = ? AND target_id = ?", "member", id).Update("target_name", ErasedMemberName)
+		if result.Error != nil {
+			return result.Error
+		}
+		receipt.FeedbackRelabelled = result.RowsAffected
+
+		result = tx.Model(&models.Reminder{}).Where("target_type = ? AND target_id = ?", "member", id).Update("target_name", ErasedMemberName)
+		if result.Error != nil {
+			return result.Error
+		}
+		receipt.RemindersRelabelled = result.RowsAffected
+
+		result = tx.Model(&models.Goal{}).Where("owner_type = ? AND owner_id = ?", "member", id).Update("owner_name", ErasedMemberName)
+		if result.Error != nil {
+			return result.Error
+		}
+		receipt.GoalsRelabelled = result.RowsAffected
+
+		if receipt.AuditEntriesScrubbed, err = scrubber.scrubAuditEntries(tx); err != nil {
+			return err
+		}
+		if receipt.WebhookPayloadsScrubbed, err = scrubber.scrubDeliveries(tx); err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.NotificationPreference{}, "member_id = ?", id).Error
+	})
+	if err != nil {
+		return receipt, databaseError("Failed to erase team member", err)
+	}
+	receipt.StreamEventsScrubbed = int64(events.Stream.Rewrite(scrubber.scrubEvent))
+
+	removeImages(ctx, "avatars", member.ID, member.Picture)
+
+	receipt.Signature = SignReceipt(SigningKey, receipt)
+	events.Emit(events.MemberErased, receipt)
+	return receipt, nil
+}
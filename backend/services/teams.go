@@ -0,0 +1,205 @@
+package services
+
+import (
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+)
+
+func validateTeam(team *models.Team) error {
+	if strings.TrimSpace(team.Name) == "" {
+		return fmt.Errorf("team name is required")
+	}
+	if len(strings.TrimSpace(team.Name)) < 2 {
+		return fmt.Errorf("team name must be at least 2 characters")
+	}
+	if len(strings.TrimSpace(team.Name)) > 50 {
+		return fmt.Errorf("team name must be less than 50 characters")
+	}
+	return nil
+}
+
+func ListTeams(ctx context.Context) ([]models.Team, *Error) {
+	var teams []models.Team
+	if err := database.DB.WithContext(ctx).Preload("Members").Find(&teams).Error; err != nil {
+		return nil, databaseError("Failed to fetch teams", err)
+	}
+	return teams, nil
+}
+
+func GetTeam(ctx context.Context, id string) (models.Team, *Error) {
+	var team models.Team
+	if err := database.DB.WithContext(ctx).Preload("Members").First(&team, "id = ?", id).Error; err != nil {
+		return team, notFoundError("Team not found", "The requested team does not exist", err)
+	}
+	return team, nil
+}
+
+func CreateTeam(ctx context.Context, team *models.Team) *Error {
+	if err := validateTeam(team); err != nil {
+		return validationError(err)
+	}
+
+	team.ID = uuid.New().String()
+	team.Name = strings.TrimSpace(team.Name)
+	team.Logo = strings.TrimSpace(team.Logo)
+	if isGeneratedURL(team.Logo) {
+		team.Logo = ""
+	}
+	if err := checkMediaURL("logo", team.Logo, ""); err != nil {
+		return err
+	}
+
+	if err := database.DB.WithContext(ctx).Create(team).Error; err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return conflictError("Team already exists", "A team with this name already exists", err)
+		}
+		return databaseError("Failed to create team", err)
+	}
+
+	events.Emit(events.TeamCreated, *team)
+	return nil
+}
+
+func UpdateTeam(ctx context.Context, id string, updateData models.Team) (models.Team, *Error) {
+	var team models.Team
+	if err := database.DB.WithContext(ctx).First(&team, "id = ?", id).Error; err != nil {
+		return team, notFoundError("Team not found", "The requested team does not exist", err)
+	}
+
+	if err := validateTeam(&updateData); err != nil {
+		return team, validationError(err)
+	}
+
+	updateData.Name = strings.TrimSpace(updateData.Name)
+	updateData.Logo = strings.TrimSpace(updateData.Logo)
+	if isGeneratedURL(updateData.Logo) {
+		updateData.Logo = ""
+	}
+	if err := checkMediaURL("logo", updateData.Logo, team.Logo); err != nil {
+		return team, err
+	}
+
+	if err := database.DB.WithContext(ctx).Model(&team).Updates(updateData).Error; err != nil {
+		return team, databaseError("Failed to update team", err)
+	}
+
+	events.Emit(events.TeamUpdated, team)
+	return team, nil
+}
+
+func DeleteTeam(ctx context.Context, id string) *Error {
+	var logos []string
+	database.DB.WithContext(ctx).Model(&models.Team{}).Where("id = ?", id).Pluck("logo", &logos)
+
+	var deleted int64
+	var goals []string
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Team{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		if deleted == 0 {
+			return nil
+		}
+		var err error
+		goals, err = deleteOwnerGoals(tx, "team", id)
+		return err
+	})
+	if err != nil {
+		return databaseError("Failed to delete team", err)
+	}
+
+	if deleted == 0 {
+		return notFoundError("Team not found", "The requested team does not exist", nil)
+	}
+
+	for _, logo := range logos {
+		removeImages(ctx, "logos", id, logo)
+	}
+
+	for _, goal := range goals {
+		events.Emit(events.GoalDeleted, events.Ref{ID: goal})
+	}
+	events.Emit(events.TeamDeleted, events.Ref{ID: id})
+	return nil
+}
+
+func sameTeam(a, b *string) bool {
+	if a == nil || *a == "" {
+		return b == nil || *b == ""
+	}
+	return b != nil && *a == *b
+}
+
+func changeMembership(ctx context.Context, member *models.TeamMember, teamID *string, expectedTeamID *string) *Error {
+	if expectedTeamID != nil && !sameTeam(member.TeamID, expectedTeamID) {
+		return conflictError("Conflict", "The member was moved by someone else, please reload and try again", nil)
+	}
+
+	query := database.DB.WithContext(ctx).Model(&models.TeamMember{}).Where("id = ?", member.ID)
+	if member.TeamID == nil {
+		query = query.Where("team_id IS NULL")
+	} else {
+		query = query.Where("team_id = ?", *member.TeamID)
+	}
+
+	result := query.Update("team_id", teamID)
+	if result.Error != nil {
+		return databaseError("Failed to update team membership", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return conflictError("Conflict", "The member was moved by someone else, please reload and try again", nil)
+	}
+
+	member.TeamID = teamID
+	return nil
+}
+
+func AssignMember(ctx context.Context, memberID, teamID string, expectedTeamID *string) (models.TeamMember, *Error) {
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", memberID).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+
+	var team models.Team
+	if err := database.DB.WithContext(ctx).First(&team, "id = ?", teamID).Error; err != nil {
+		return member, notFoundError("Team not found", "The requested team does not exist", err)
+	}
+
+	previousTeamID := member.TeamID
+	if err := changeMembership(ctx, &member, &teamID, expectedTeamID); err != nil {
+		return member, err
+	}
+
+	events.Emit(events.MemberAssigned, events.MembershipChange{
+		Member:         member,
+		TeamID:         member.TeamID,
+		PreviousTeamID: previousTeamID,
+	})
+	return member, nil
+}
+
+func RemoveMember(ctx context.Context, memberID string, expectedTeamID *string) (models.TeamMember, *Error) {
+	var member models.TeamMember
+	if err := database.DB.WithContext(ctx).First(&member, "id = ?", memberID).Error; err != nil {
+		return member, notFoundError("Member not found", "The requested team member does not exist", err)
+	}
+
+	previousTeamID := member.TeamID
+	if err := changeMembership(ctx, &member, nil, expectedTeamID); err != nil {
+		return member, err
+	}
+
+	events.Emit(events.MemberUnassigned, events.MembershipChange{
+		Member:         member,
+		PreviousTeamID: previousTeamID,
+	})
+	return member, nil
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	before := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			startSpan(tx, operation)
+		}
+	}
+
+	return errors.Join(
+		db.Callback().Create().Before("gorm:create").Register("tracing:before_create", before("create")),
+		db.Callback().Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		db.Callback().Query().Before("gorm:query").Register("tracing:before_query", before("query")),
+		db.Callback().Query().After("gorm:preload").Register("tracing:after_query", endSpan),
+		db.Callback().Update().Before("gorm:update").Register("tracing:before_update", before("update")),
+		db.Callback().Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		db.Callback().Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")),
+		db.Callback().Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		db.Callback().Row().Before("gorm:row").Register("tracing:before_row", before("row")),
+		db.Callback().Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		db.Callback().Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")),
+		db.Callback().Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(tx *gorm.DB, operation string) {
+	if tx.DryRun || tx.Statement.Context == nil {
+		return
+	}
+	parent := trace.SpanFromContext(tx.Statement.Context)
+	if !parent.SpanContext().IsValid() {
+		return
+	}
+
+	name := "gorm." + operation
+	if tx.Statement.Table != "" {
+		name += " " + tx.Statement.Table
+	}
+	ctx, span := Tracer().Start(tx.Statement.Context, name, trace.WithSpanKind(trace.SpanKindClient))
+	tx.Statement.Context = ctx
+	tx.InstanceSet(gormSpanKey, span)
+}
+
+func endSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBSystemKey.String(tx.Dialector.Name()),
+		semconv.DBQueryText(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"coaching-backend/config"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"strings"
+)
+
+const instrumentationName = "coaching-backend"
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+func newExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, error) {
+	exporter := cfg.Exporter
+	if exporter == "" {
+		exporter = "none"
+		if cfg.Endpoint != "" || cfg.TracesEndpoint != "" {
+			exporter = "otlp"
+		}
+	}
+
+	switch exporter {
+	case "otlp":
+		var opts []otlptracehttp.Option
+		switch {
+		case cfg.TracesEndpoint != "":
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.TracesEndpoint))
+		case cfg.Endpoint != "":
+			opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case "stdout", "console":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "none":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q, expected otlp, stdout or none", exporter)
+}
+
+func Setup(ctx context.Context, cfg config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		slog.Info("Tracing disabled, set tracing.exporter or tracing.endpoint to enable it")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	slog.Info("Tracing enabled", "exporter", fmt.Sprintf("%T", exporter))
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,299 @@
+package webhooks
+
+import (
+	"bytes"
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+	MaxAttempts     = 8
+	BackoffBase     = 10 * time.Second
+	BackoffMax      = time.Hour
+	SubscriptionTTL = 30 * time.Second
+
+	AllowedNetworks []*net.IPNet
+
+	httpClient = newHTTPClient()
+
+	reservedNetworks = parseNetworks("0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4")
+
+	cacheMu      sync.Mutex
+	cached       []models.WebhookSubscription
+	cachedAt     time.Time
+	cacheVersion uint64
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic("invalid network " + cidr)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: checkDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: 10 * time.Second, Transport: transport}
+}
+
+func checkDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("webhook target %s is not an IP address", host)
+	}
+	return CheckIP(ip)
+}
+
+func CheckIP(ip net.IP) error {
+	for _, network := range AllowedNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	blocked := ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+	for _, network := range reservedNetworks {
+		blocked = blocked || network.Contains(ip)
+	}
+	if blocked {
+		return fmt.Errorf("webhook target %s is not a public address", ip)
+	}
+	return nil
+}
+
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret, timestamp string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+func Subscribes(subscription models.WebhookSubscription, eventType string) bool {
+	if subscription.Active != nil && !*subscription.Active {
+		return false
+	}
+	for _, e := range subscription.Events {
+		if e == "*" || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func InvalidateSubscriptions() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cached = nil
+	cacheVersion++
+}
+
+func activeSubscriptions() ([]models.WebhookSubscription, error) {
+	cacheMu.Lock()
+	if cached != nil && time.Since(cachedAt) < SubscriptionTTL {
+		defer cacheMu.Unlock()
+		return cached, nil
+	}
+	version := cacheVersion
+	cacheMu.Unlock()
+
+	subscriptions := []models.WebhookSubscription{}
+	if err := database.DB.Where("active IS NULL OR active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if version == cacheVersion {
+		cached = subscriptions
+		cachedAt = time.Now()
+	}
+	return subscriptions, nil
+}
+
+func Enqueue(event events.Event) {
+	subscriptions, err := activeSubscriptions()
+	if err != nil {
+		slog.Error("Failed to load webhook subscriptions", "event_type", event.Type, "error", err)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to encode webhook event", "event_id", event.ID, "error", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if !Subscribes(subscription, event.Type) {
+			continue
+		}
+
+		delivery := models.WebhookDelivery{
+			ID:             uuid.New().String(),
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         StatusPending,
+			NextAttemptAt:  time.Now(),
+		}
+		if err := database.DB.Create(&delivery).Error; err != nil {
+			slog.Error("Failed to enqueue webhook delivery", "webhook_id", subscription.ID, "error", err)
+		}
+	}
+}
+
+func StartWorker(ctx context.Context, cfg config.Webhooks) <-chan struct{} {
+	MaxAttempts = cfg.MaxAttempts
+	SubscriptionTTL = cfg.SubscriptionTTL
+	AllowedNetworks = cfg.ParsedAllowedNetworks()
+	slog.Info("Webhook worker started", "poll_interval", cfg.PollInterval.String(), "max_attempts", MaxAttempts, "subscription_ttl", SubscriptionTTL.String())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Webhook worker stopped")
+				return
+			case <-ticker.C:
+				if _, err := ProcessPending(time.Now()); err != nil {
+					slog.Error("Webhook processing failed", "error", err)
+				}
+			}
+		}
+	}()
+	return done
+}
+
+func ProcessPending(now time.Time) (int, error) {
+	var deliveries []models.WebhookDelivery
+	if err := database.DB.Where("status = ? AND next_attempt_at <= ?", StatusPending, now).
+		Order("next_attempt_at").
+		Limit(50).
+		Find(&deliveries).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		deliver(&deliveries[i], now)
+		if err := database.DB.Save(&deliveries[i]).Error; err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func deliver(delivery *models.WebhookDelivery, now time.Time) {
+	delivery.Attempts++
+
+	var subscription models.WebhookSubscription
+	if err := database.DB.First(&subscription, "id = ?", delivery.SubscriptionID).Error; err != nil {
+		delivery.Status = StatusFailed
+		delivery.LastError = "subscription no longer exists"
+		return
+	}
+
+	statusCode, err := send(subscription, delivery, now)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = StatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= MaxAttempts {
+		delivery.Status = StatusFailed
+		slog.Warn("Webhook delivery failed permanently", "delivery_id", delivery.ID, "webhook_id", delivery.SubscriptionID, "attempts", delivery.Attempts, "error", err)
+		return
+	}
+	delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+}
+
+func send(subscription models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	payload := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "coaching-backend-webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, payload))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func Backoff(attempts int) time.Duration {
+	delay := BackoffBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= BackoffMax {
+			return BackoffMax
+		}
+	}
+	return delay
+}
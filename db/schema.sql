@@ -11,9 +11,19 @@ CREATE TABLE IF NOT EXISTS team_members (
     picture TEXT,
     email VARCHAR(255) NOT NULL UNIQUE,
     team_id VARCHAR(36),
+    title VARCHAR(100),
+    level VARCHAR(20),
+    location VARCHAR(100),
+    time_zone VARCHAR(64),
+    start_date VARCHAR(10),
+    skills TEXT,
+    legal_hold BOOLEAN NOT NULL DEFAULT FALSE,
+    erased_at TIMESTAMP NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    INDEX idx_team_id (team_id)
+    INDEX idx_team_id (team_id),
+    INDEX idx_team_members_level (level),
+    INDEX idx_team_members_legal_hold (legal_hold)
 );
 
 -- Teams Table
@@ -32,10 +42,112 @@ CREATE TABLE IF NOT EXISTS feedbacks (
     target_type ENUM('team', 'member') NOT NULL,
     target_id VARCHAR(36) NOT NULL,
     target_name VARCHAR(255),
+    author_id VARCHAR(36),
+    goal_id VARCHAR(36),
+    category VARCHAR(50),
+    legal_hold BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_target (target_type, target_id),
-    INDEX idx_created_at (created_at)
+    INDEX idx_created_at (created_at),
+    INDEX idx_feedbacks_author_id (author_id),
+    INDEX idx_feedbacks_goal_id (goal_id),
+    INDEX idx_feedbacks_category (category),
+    INDEX idx_feedbacks_legal_hold (legal_hold)
+);
+
+-- Goals Table
+CREATE TABLE IF NOT EXISTS goals (
+    id VARCHAR(36) PRIMARY KEY,
+    owner_type VARCHAR(10) NOT NULL,
+    owner_id VARCHAR(36) NOT NULL,
+    owner_name VARCHAR(255),
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    target_date VARCHAR(10),
+    status VARCHAR(20) NOT NULL,
+    progress INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_goal_owner (owner_type, owner_id),
+    INDEX idx_goals_status (status)
+);
+
+-- Reminders Table
+CREATE TABLE IF NOT EXISTS reminders (
+    id VARCHAR(36) PRIMARY KEY,
+    target_type VARCHAR(10) NOT NULL,
+    target_id VARCHAR(36) NOT NULL,
+    target_name VARCHAR(255),
+    last_feedback_at TIMESTAMP NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    open_key VARCHAR(50) NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_reminder_target (target_type, target_id),
+    INDEX idx_reminder_status (status),
+    UNIQUE INDEX idx_reminders_open_key (open_key)
+);
+
+-- Webhook Subscriptions Table
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(36) PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Webhook Deliveries Table
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    subscription_id VARCHAR(36) NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_subscription_id (subscription_id),
+    INDEX idx_delivery_queue (status, next_attempt_at)
+);
+
+-- Notification Preferences Table
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    member_id VARCHAR(36) PRIMARY KEY,
+    email_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    team_feedback_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Feedback Search Tokens Table (blind index used when feedback content is encrypted)
+CREATE TABLE IF NOT EXISTS feedback_search_tokens (
+    feedback_id VARCHAR(36) NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    PRIMARY KEY (feedback_id, token),
+    INDEX idx_token (token)
+);
+
+-- Audit Entries Table
+CREATE TABLE IF NOT EXISTS audit_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_entries_action (action),
+    INDEX idx_audit_entity (entity_type, entity_id),
+    INDEX idx_audit_entries_created_at (created_at)
 );
 
 -- Add Foreign Key Constraints
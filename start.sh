@@ -16,6 +16,14 @@ fi
 
 echo "✅ Docker and Docker Compose are installed"
 
+for example in db/secrets/*.example; do
+    secret="${example%.example}"
+    if [ ! -f "$secret" ]; then
+        cp "$example" "$secret"
+        echo "⚠️  Created $secret from the example, replace the placeholder credentials before sharing this environment"
+    fi
+done
+
 echo "🏗️  Building and starting all services..."
 docker-compose down --remove-orphans
 docker-compose build --no-cache